@@ -0,0 +1,115 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Alias registers aliases, beneath the current point, as further
+// patterns for the route at canonical, and returns the canonical node
+// to allow chaining, as in:
+//
+//     r.Alias("/signup", "/register", "/join").Name("signup").FuncE(signup)
+//
+// Requests to an alias are served exactly as requests to canonical:
+// by its handler, variants and middleware, whenever they are
+// registered.  Only the middleware of the canonical route applies,
+// and the Pattern reported is canonical.  Reverse routing, through
+// URL and FuncMap, always builds the canonical path.
+//
+// Every variable and "*" of canonical must be captured by each alias.
+// The aliases are registered all at once: Alias panics, registering
+// none of them, if one is already a route with a handler or variants.
+func (r *Router) Alias(canonical string, aliases ...string) *Router {
+	for _, a := range aliases {
+		for _, part := range strings.Split(canonical, "/") {
+			if (part == "*" || strings.HasPrefix(part, ":")) && !strings.Contains(a+"/", "/"+part+"/") {
+				panic("route: Alias " + a + ": " + part + " of " + canonical + " is not captured")
+			}
+		}
+	}
+	defer r.lock()()
+	// Check every path before changing the tree: against the tree, and
+	// against each other in a scratch tree.
+	scratch := &Router{pattern: r.pattern, Strict: r.Strict, CaseInsensitive: r.CaseInsensitive}
+	seen := map[*Router]bool{}
+	for i, path := range append([]string{canonical}, aliases...) {
+		n, err := r.findRoute(path)
+		if err != nil {
+			panic(err.Error())
+		}
+		s, err := scratch.routePath(path)
+		if err != nil {
+			panic(err.Error())
+		}
+		if i > 0 && (seen[s] || seen[n] || (n != nil && (n.handler != nil || len(n.variants) > 0))) {
+			panic("route: Alias " + path + ": duplicate handler")
+		}
+		seen[s] = true
+		if n != nil {
+			seen[n] = true
+		}
+	}
+	c, err := r.routePath(canonical)
+	if err != nil {
+		panic(err.Error())
+	}
+	for _, a := range aliases {
+		n, err := r.routePath(a)
+		if err != nil {
+			panic(err.Error())
+		}
+		n.setAlias(c)
+	}
+	return c
+}
+
+// setAlias makes n an alias of c.  Its handler, which serve bypasses
+// (see aliasPath), is only reached through Handler and the like, and
+// serves the request again at the canonical path.
+func (n *Router) setAlias(c *Router) {
+	n.alias = c
+	n.setHandler(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		n.root().ServeHTTP(w, stripRequest(req, n.aliasPath(env)))
+	}, "route.Alias("+c.displayPattern()+")")
+}
+
+// aliasPath returns the path of n's canonical route for the values
+// captured by n.
+func (n *Router) aliasPath(env map[string]string) string {
+	if n.alias.pattern == "" {
+		return "/"
+	}
+	parts := strings.Split(n.alias.pattern[1:], "/")
+	for i, part := range parts {
+		switch {
+		case part == "*":
+			parts[i] = env["*"]
+		case strings.HasPrefix(part, ":"):
+			parts[i] = env[part[1:]]
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// relinkAliases points the aliases beneath r that were merged from
+// another tree, and still refer to its nodes, at the nodes of r's tree
+// with the same pattern under prefix.
+func (r *Router) relinkAliases(prefix string) {
+	root := r.root()
+	r.walk(func(n *Router) error {
+		if n.alias == nil || n.alias.root() == root {
+			return nil
+		}
+		pattern := prefix + n.alias.pattern
+		root.walk(func(c *Router) error {
+			if c.pattern == pattern {
+				n.handler = nil
+				n.setAlias(c)
+				return errFound
+			}
+			return nil
+		})
+		return nil
+	})
+}
@@ -0,0 +1,106 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlias(t *testing.T) {
+	r := &Router{}
+	var pattern string
+	echo := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		pattern = req.Pattern
+		w.Write([]byte(env["id"] + env["*"]))
+	}
+	r.Alias("/signup", "/register", "/join").Name("signup").Func(writeString("signup"))
+	r.Alias("/users/:id", "/u/:id").Name("user").FuncE(echo)
+	r.Alias("/files/*", "/f/*").FuncE(echo)
+
+	assert.Equal(t, "signup", get(r, "/register").Body.String())
+	assert.Equal(t, "signup", get(r, "/join").Body.String())
+	assert.Equal(t, "signup", get(r, "/signup").Body.String())
+	assert.Equal(t, "42", get(r, "/u/42").Body.String())
+	assert.Equal(t, "/users/:id", pattern)
+	assert.Equal(t, "a/b", get(r, "/f/a/b").Body.String())
+	assert.Equal(t, "/files/*", pattern)
+
+	url, err := r.URL("user", 42)
+	assert.Nil(t, err)
+	assert.Equal(t, "/users/42", url)
+	url, err = r.URL("signup")
+	assert.Nil(t, err)
+	assert.Equal(t, "/signup", url)
+
+	r.Compile()
+	assert.Equal(t, "7", get(r, "/u/7").Body.String())
+}
+
+func TestAliasMiddleware(t *testing.T) {
+	r := &Router{}
+	calls := 0
+	count := func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			calls++
+			next(w, req, env)
+		}
+	}
+	r.Use(count)
+	api := r.Route("/api")
+	api.SetHeader("X-API", "1")
+	r.Alias("/api/items", "/items")
+	api.Route("/items").Method(http.MethodPost).Func(writeString("created"))
+
+	w := post(r, "/items", "")
+	assert.Equal(t, "created", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-API"))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusMethodNotAllowed, get(r, "/items").Code)
+}
+
+func TestAliasConflicts(t *testing.T) {
+	r := &Router{}
+	r.Route("/taken").Func(writeString("taken"))
+	assert.Panics(t, func() { r.Alias("/home", "/index", "/taken") })
+	assert.Equal(t, http.StatusNotFound, get(r, "/index").Code)
+	assert.Equal(t, "taken", get(r, "/taken").Body.String())
+
+	assert.Panics(t, func() { r.Alias("/users/:id", "/people") })
+	assert.Panics(t, func() { r.Alias("/home", "/home") })
+	assert.Panics(t, func() { r.Alias("/about", "/info", "/info") })
+	assert.Panics(t, func() { r.Alias("/a/:id", "/b/:id", "/b/:x/:id") })
+
+	// Nothing was registered by the failed calls.
+	for _, path := range []string{"/home", "/index", "/about", "/info", "/a", "/b"} {
+		assert.Nil(t, r.lookupPath(path, nil), path)
+		_, ok := r.Match("GET", path)
+		assert.False(t, ok, path)
+	}
+	assert.NotContains(t, r.DumpString(), "/home")
+	assert.NotContains(t, r.DumpString(), "/b")
+}
+
+func TestAliasMatch(t *testing.T) {
+	r := &Router{}
+	r.Alias("/users/:id", "/u/:id").Name("user").FuncE(F1)
+	m, ok := r.Match("GET", "/u/5")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/:id", m.Pattern)
+	assert.Equal(t, "user", m.Name)
+	assert.Equal(t, map[string]string{"id": "5"}, m.Vars)
+}
+
+func TestAliasMerge(t *testing.T) {
+	api := &Router{}
+	api.Alias("/users/:id", "/u/:id").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		w.Write([]byte(req.Pattern))
+	})
+	r := &Router{}
+	assert.Nil(t, r.Merge(api, "/api"))
+	assert.Equal(t, "/api/users/:id", get(r, "/api/u/1").Body.String())
+
+	r2 := &Router{}
+	assert.Nil(t, r2.Route("/v1").Swap(r))
+	assert.Equal(t, "/v1/api/users/:id", get(r2, "/v1/api/u/1").Body.String())
+}
@@ -0,0 +1,106 @@
+package route
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// assetSet is a set of fingerprinted files; see Assets.
+type assetSet struct {
+	fsys   fs.FS
+	hashes map[string]string // file name => content hash
+}
+
+// Assets serves the files in fsys beneath the current point under
+// fingerprinted URLs, "/:hash/name", with headers letting clients
+// cache them forever.  When a file changes so does its URL, so clients
+// fetch the new version.  Build URLs with AssetURL, or the "asset"
+// template function of FuncMap:
+//
+//     r.Route("/assets").Assets(static)
+//     url, _ := r.AssetURL("js/app.js") // "/assets/1f2e3d4c5b6a7988/js/app.js"
+//
+// The files are hashed when Assets is called, so fsys should not
+// change afterwards.  Requests with an outdated hash are redirected
+// to the current URL.
+func (r *Router) Assets(fsys fs.FS) error {
+	a := &assetSet{fsys: fsys, hashes: map[string]string{}}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		a.hashes[name] = hex.EncodeToString(sum[:8])
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("route: hashing assets: %w", err)
+	}
+
+	n, err := r.RouteE(":hash/*")
+	if err != nil {
+		return err
+	}
+	defer r.lock()()
+	if err := n.setBoundHandler(a.serve, "route.Assets"); err != nil {
+		return err
+	}
+	r.assets = a
+	return nil
+}
+
+// serve returns the handler for n, the ":hash/*" node beneath the node
+// the assets are served at.
+func (a *assetSet) serve(n *Router) HandlerE {
+	at := n.parent.parent
+	return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		name, ok := fileName(env["*"])
+		hash := a.hashes[name]
+		if !ok || hash == "" {
+			http.NotFound(w, req)
+			return
+		}
+		if env["hash"] != hash {
+			http.Redirect(w, req, a.url(at, name), http.StatusFound)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		serveFile(w, req, a.fsys, name)
+	}
+}
+
+// url returns the URL for the asset name served at node at.
+func (a *assetSet) url(at *Router, name string) string {
+	return at.pattern + "/" + a.hashes[name] + "/" + name
+}
+
+// errFound stops a walk once it has found what it is looking for.
+var errFound = errors.New("found")
+
+// AssetURL returns the fingerprinted URL of the file name served by
+// Assets beneath r.
+func (r *Router) AssetURL(name string) (string, error) {
+	defer r.rlock()()
+	name = strings.TrimPrefix(name, "/")
+	var url string
+	err := r.walk(func(n *Router) error {
+		if n.assets != nil && n.assets.hashes[name] != "" {
+			url = n.assets.url(n, name)
+			return errFound
+		}
+		return nil
+	})
+	if err != errFound {
+		return "", fmt.Errorf("route: no asset %q", name)
+	}
+	return url, nil
+}
@@ -0,0 +1,50 @@
+package route
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"js/app.js": {Data: []byte("app()")},
+		"site.css":  {Data: []byte("body {}")},
+	}
+	r := &Router{}
+	assert.Nil(t, r.Route("/assets").Assets(fsys))
+
+	u, err := r.AssetURL("js/app.js")
+	assert.Nil(t, err)
+	assert.Regexp(t, `^/assets/[0-9a-f]{16}/js/app\.js$`, u)
+	_, err = r.AssetURL("missing.js")
+	assert.NotNil(t, err)
+
+	w := get(r, u)
+	assert.Equal(t, "app()", w.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = get(r, "/assets/0000000000000000/js/app.js")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, u, w.Header().Get("Location"))
+	assert.Equal(t, http.StatusNotFound, get(r, "/assets/0000000000000000/missing.js").Code)
+
+	css, _ := r.AssetURL("site.css")
+	assert.NotEqual(t, u[len("/assets/"):][:16], css[len("/assets/"):][:16])
+
+	tmpl := template.Must(template.New("t").Funcs(r.FuncMap()).Parse(`<script src="{{ asset "js/app.js" }}"></script>`))
+	var buf bytes.Buffer
+	assert.Nil(t, tmpl.Execute(&buf, nil))
+	assert.Equal(t, `<script src="`+u+`"></script>`, buf.String())
+
+	// Merged elsewhere, old hashes redirect to the new place.
+	site := &Router{}
+	assert.Nil(t, site.Merge(r, "/static"))
+	w = get(site, "/static/assets/0000000000000000/js/app.js")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/static"+u, w.Header().Get("Location"))
+}
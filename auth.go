@@ -0,0 +1,175 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Identity is the client authenticated by Auth.
+type Identity struct {
+	// Subject identifies the client, e.g. a user name.
+	Subject string
+	// Scopes are the permissions or roles the client holds, checked
+	// against those required by RequireScopes.
+	Scopes []string
+}
+
+// HasScope reports whether id holds scope.
+func (id *Identity) HasScope(scope string) bool {
+	for _, s := range id.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Auth is middleware authenticating requests with the Basic and Bearer
+// schemes of the Authorization header, leaving verification of the
+// credentials to the application:
+//
+//     r.Use(route.Auth{
+//         Realm:  "api",
+//         Bearer: func(req *http.Request, token string) (*route.Identity, error) {
+//             return tokens.Lookup(req.Context(), token)
+//         },
+//     }.Middleware())
+//     r.Route("/admin").RequireScopes("admin")
+//
+// A verification function returns nil, without an error, for bad
+// credentials.  Requests without valid credentials for one of the
+// configured schemes get a 401 Unauthorized with a WWW-Authenticate
+// challenge, unless Optional is set.  Errors from verification are
+// logged and get a 500 Internal Server Error.  Handlers find the
+// client with Authenticated.
+type Auth struct {
+	// Realm is named in challenges.
+	Realm string
+
+	// Basic, if set, verifies Basic credentials.
+	Basic func(req *http.Request, user, password string) (*Identity, error)
+
+	// Bearer, if set, verifies Bearer tokens.
+	Bearer func(req *http.Request, token string) (*Identity, error)
+
+	// Optional lets requests without credentials through
+	// unauthenticated, for subtrees mixing public and private routes;
+	// RequireScopes still rejects them.  Bad credentials are rejected
+	// regardless.
+	Optional bool
+}
+
+// ScopesMeta is the metadata key under which RequireScopes records the
+// scopes a route requires; see Router.Meta.
+const ScopesMeta = "scopes"
+
+// authKey is the context key of a request's authInfo.
+type authKey struct{}
+
+// authInfo is what Auth records about a request.
+type authInfo struct {
+	id        *Identity
+	challenge string
+}
+
+// Middleware returns middleware applying a.
+func (a Auth) Middleware() Middleware {
+	var challenges []string
+	if a.Basic != nil {
+		challenges = append(challenges, `Basic realm="`+a.Realm+`", charset="UTF-8"`)
+	}
+	if a.Bearer != nil {
+		challenges = append(challenges, `Bearer realm="`+a.Realm+`"`)
+	}
+	challenge := strings.Join(challenges, ", ")
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			id, given, err := a.verify(req)
+			if err != nil {
+				logf(req, "verifying credentials: %v", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if id == nil && (given || !a.Optional) {
+				unauthorized(w, challenge)
+				return
+			}
+			req = req.WithContext(context.WithValue(req.Context(), authKey{}, &authInfo{id, challenge}))
+			next(w, req, env)
+		}
+	}
+}
+
+// verify checks the credentials of req, reporting whether any were
+// given for a configured scheme.
+func (a *Auth) verify(req *http.Request) (*Identity, bool, error) {
+	h := req.Header.Get("Authorization")
+	scheme, cred, _ := strings.Cut(h, " ")
+	switch {
+	case a.Basic != nil && strings.EqualFold(scheme, "Basic"):
+		user, password, ok := req.BasicAuth()
+		if !ok {
+			return nil, true, nil
+		}
+		id, err := a.Basic(req, user, password)
+		return id, true, err
+	case a.Bearer != nil && strings.EqualFold(scheme, "Bearer"):
+		id, err := a.Bearer(req, strings.TrimSpace(cred))
+		return id, true, err
+	}
+	return nil, false, nil
+}
+
+// unauthorized responds with a 401 and challenge.
+func unauthorized(w http.ResponseWriter, challenge string) {
+	if challenge != "" {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// Authenticated returns the client authenticated by Auth for req, or
+// nil if there is none.
+func Authenticated(req *http.Request) *Identity {
+	if info, _ := req.Context().Value(authKey{}).(*authInfo); info != nil {
+		return info.id
+	}
+	return nil
+}
+
+// RequireScopes declares that routes at and beneath the current point
+// may only be used by clients authenticated by Auth holding all of
+// scopes, and returns the router to allow chaining.  The scopes are
+// recorded in the route's metadata under ScopesMeta, where tools like
+// documentation generators find them, and are enforced from there:
+// unauthenticated requests get a 401 Unauthorized, and clients lacking
+// a scope a 403 Forbidden.  Scopes required at several levels of the
+// tree must all be held.
+func (r *Router) RequireScopes(scopes ...string) *Router {
+	r.SetMeta(ScopesMeta, scopes)
+	n := r
+	return r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			unlock := n.rlock()
+			required, _ := n.meta[ScopesMeta].([]string)
+			unlock()
+			info, _ := req.Context().Value(authKey{}).(*authInfo)
+			if info == nil || info.id == nil {
+				challenge := ""
+				if info != nil {
+					challenge = info.challenge
+				}
+				unauthorized(w, challenge)
+				return
+			}
+			for _, s := range required {
+				if !info.id.HasScope(s) {
+					http.Error(w, "missing scope "+s, http.StatusForbidden)
+					return
+				}
+			}
+			next(w, req, env)
+		}
+	})
+}
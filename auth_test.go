@@ -0,0 +1,83 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth(t *testing.T) {
+	r := &Router{}
+	r.Use(Auth{
+		Realm: "test",
+		Basic: func(req *http.Request, user, password string) (*Identity, error) {
+			if user == "ann" && password == "secret" {
+				return &Identity{Subject: "ann", Scopes: []string{"admin"}}, nil
+			}
+			return nil, nil
+		},
+		Bearer: func(req *http.Request, token string) (*Identity, error) {
+			switch token {
+			case "t1":
+				return &Identity{Subject: "bot"}, nil
+			case "boom":
+				return nil, errors.New("token store down")
+			}
+			return nil, nil
+		},
+		Optional: true,
+	}.Middleware())
+	whoami := func(w http.ResponseWriter, req *http.Request) {
+		if id := Authenticated(req); id != nil {
+			w.Write([]byte(id.Subject))
+		}
+	}
+	r.Route("/public").Func(whoami)
+	r.Route("/admin").RequireScopes("admin").Route("/users").Func(whoami)
+
+	do := func(path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "", do("/public", "").Body.String())
+	assert.Equal(t, "bot", do("/public", "Bearer t1").Body.String())
+	assert.Equal(t, http.StatusUnauthorized, do("/public", "Bearer bad").Code)
+	assert.Equal(t, http.StatusInternalServerError, do("/public", "Bearer boom").Code)
+
+	w := do("/admin/users", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="test", charset="UTF-8", Bearer realm="test"`, w.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusForbidden, do("/admin/users", "Bearer t1").Code)
+	assert.Equal(t, "ann", do("/admin/users", "Basic YW5uOnNlY3JldA==").Body.String())
+
+	// The requirement is read from the route's metadata.
+	assert.Equal(t, []string{"admin"}, r.Route("/admin").Meta()[ScopesMeta])
+	r.Route("/admin").SetMeta(ScopesMeta, []string{})
+	assert.Equal(t, "bot", do("/admin/users", "Bearer t1").Body.String())
+}
+
+func TestAuthRequired(t *testing.T) {
+	r := &Router{}
+	r.Use(Auth{Bearer: func(req *http.Request, token string) (*Identity, error) {
+		return &Identity{Subject: token}, nil
+	}}.Middleware())
+	r.Route("/").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(Authenticated(req).Subject))
+	})
+	assert.Equal(t, http.StatusUnauthorized, get(r, "/").Code)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer x")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "x", w.Body.String())
+}
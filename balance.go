@@ -0,0 +1,96 @@
+package route
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// Upstream is a backend in a pool served by ProxyPool.
+type Upstream struct {
+	URL *url.URL
+
+	// active counts requests in flight; fails counts failed requests
+	// in a row, and downUntil is when a backend taken out of the pool
+	// for failing, in Unix nanoseconds, returns.
+	active    atomic.Int64
+	fails     atomic.Int64
+	downUntil atomic.Int64
+}
+
+// Active returns the number of requests u is serving.
+func (u *Upstream) Active() int64 { return u.active.Load() }
+
+// Balancer chooses the backend for a request proxied by ProxyPool.
+type Balancer interface {
+	// Pick returns one of ups, which is never empty, to serve req.
+	// It is called concurrently.
+	Pick(ups []*Upstream, req *http.Request) *Upstream
+}
+
+// RoundRobin is a Balancer using each backend in turn.
+type RoundRobin struct {
+	next atomic.Uint64
+}
+
+func (b *RoundRobin) Pick(ups []*Upstream, req *http.Request) *Upstream {
+	return ups[(b.next.Add(1)-1)%uint64(len(ups))]
+}
+
+// LeastConnections is a Balancer using the backend with the fewest
+// requests in flight, the first listed among ties.
+type LeastConnections struct{}
+
+func (LeastConnections) Pick(ups []*Upstream, req *http.Request) *Upstream {
+	best := ups[0]
+	for _, u := range ups[1:] {
+		if u.Active() < best.Active() {
+			best = u
+		}
+	}
+	return best
+}
+
+// upstreamPool is the set of backends of a proxy route.
+type upstreamPool struct {
+	upstreams   []*Upstream
+	balancer    Balancer
+	maxFails    int
+	failTimeout time.Duration
+}
+
+// pick chooses the backend for req among the healthy ones, or among
+// all of them if none is healthy.
+func (p *upstreamPool) pick(req *http.Request) *Upstream {
+	if len(p.upstreams) == 1 {
+		return p.upstreams[0]
+	}
+	now := timeNow().UnixNano()
+	ups := p.upstreams
+	for i, u := range ups {
+		if u.downUntil.Load() > now {
+			// Copy the healthy ones only once one is found not to be.
+			healthy := append([]*Upstream{}, ups[:i]...)
+			for _, u := range ups[i+1:] {
+				if u.downUntil.Load() <= now {
+					healthy = append(healthy, u)
+				}
+			}
+			if len(healthy) > 0 {
+				ups = healthy
+			}
+			break
+		}
+	}
+	return p.balancer.Pick(ups, req)
+}
+
+// fail records a failed request to u, taking it out of the pool if
+// it has failed too often.
+func (p *upstreamPool) fail(u *Upstream) {
+	if u.fails.Add(1) >= int64(p.maxFails) {
+		u.fails.Store(0)
+		u.downUntil.Store(timeNow().Add(p.failTimeout).UnixNano())
+	}
+}
@@ -0,0 +1,53 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyPool(t *testing.T) {
+	now := fakeClock(t)
+	var targets []*url.URL
+	for _, name := range []string{"a", "b"} {
+		name := name
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name))
+		}))
+		defer s.Close()
+		u, _ := url.Parse(s.URL)
+		targets = append(targets, u)
+	}
+	down, _ := url.Parse("http://127.0.0.1:1")
+
+	r := &Router{}
+	r.Route("/rr/*").ProxyPool(targets)
+	r.Route("/health/*").ProxyPool([]*url.URL{down, targets[0]}, ProxyOptions{MaxFails: 2, FailTimeout: time.Minute})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, get(r, "/rr/").Body.String())
+	}
+	assert.Equal(t, []string{"a", "b", "a", "b"}, got)
+
+	// The failing backend is skipped once it has failed MaxFails times.
+	got = nil
+	for i := 0; i < 6; i++ {
+		got = append(got, get(r, "/health/").Body.String())
+	}
+	assert.Equal(t, []string{"Bad Gateway\n", "a", "Bad Gateway\n", "a", "a", "a"}, got)
+	*now = now.Add(2 * time.Minute)
+	assert.Equal(t, http.StatusBadGateway, get(r, "/health/").Code)
+}
+
+func TestLeastConnections(t *testing.T) {
+	ups := []*Upstream{{}, {}, {}}
+	ups[0].active.Store(2)
+	ups[1].active.Store(1)
+	ups[2].active.Store(1)
+	assert.Equal(t, ups[1], LeastConnections{}.Pick(ups, nil))
+}
@@ -0,0 +1,227 @@
+package route
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// bindSources are the struct tags understood by Bind, and what their
+// values are called in errors.
+var bindSources = []struct{ tag, desc string }{
+	{"path", "path variable"},
+	{"query", "query parameter"},
+	{"form", "form field"},
+}
+
+// Bind fills the fields of the struct pointed to by dst from the
+// request's path variables in env, its query parameters and its form
+// fields, as named by their struct tags:
+//
+//     var in struct {
+//         ID    int       `path:"id"`
+//         Page  int       `query:"page"`
+//         Email string    `form:"email,required"`
+//         Tags  []string  `form:"tag"`
+//         Start time.Time `form:"start"`
+//     }
+//     if err := route.Bind(req, env, &in); err != nil {
+//         return err
+//     }
+//
+// Form fields come from an application/x-www-form-urlencoded or
+// multipart/form-data body; query parameters are not form fields.
+// Fields may be strings, bools, numbers, byte slices, types
+// implementing encoding.TextUnmarshaler, like time.Time, or slices of
+// these, which get every value given.  Fields of embedded structs are
+// bound too.  Fields without a value are left alone, unless tagged
+// "required".
+//
+// Missing required values and malformed ones are reported as HTTPErrors
+// with status 400 Bad Request, so a FuncErr handler can return them.
+// Bind panics if dst is not a pointer to a struct, or if a tagged field
+// has a type it can't set, whatever the request.
+func Bind(req *http.Request, env map[string]string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		panic("route: Bind needs a pointer to a struct")
+	}
+	b := &binder{req: req, env: env}
+	return b.bind(v.Elem())
+}
+
+// binder holds the values of a request being bound, parsed as needed.
+type binder struct {
+	req   *http.Request
+	env   map[string]string
+	query url.Values
+	form  url.Values
+}
+
+func (b *binder) bind(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if err := b.bind(v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		for _, src := range bindSources {
+			tag, ok := f.Tag.Lookup(src.tag)
+			if !ok {
+				continue
+			}
+			if !bindable(f.Type) {
+				panic(fmt.Sprintf("route: Bind can't set field %s of type %s", f.Name, f.Type))
+			}
+			name, opt, _ := strings.Cut(tag, ",")
+			vals, err := b.values(src.tag, name)
+			if err != nil {
+				return err
+			}
+			if len(vals) == 0 {
+				if opt == "required" {
+					return Errorf(http.StatusBadRequest, "missing %s %s", src.desc, name)
+				}
+				continue
+			}
+			if err := setField(v.Field(i), vals); err != nil {
+				return Errorf(http.StatusBadRequest, "invalid %s %s %q", src.desc, name, vals[0])
+			}
+		}
+	}
+	return nil
+}
+
+// values returns the values of name in the given source.
+func (b *binder) values(src, name string) ([]string, error) {
+	switch src {
+	case "path":
+		if v, ok := b.env[name]; ok {
+			return []string{v}, nil
+		}
+		return nil, nil
+	case "query":
+		if b.query == nil {
+			b.query = b.req.URL.Query()
+		}
+		return b.query[name], nil
+	default:
+		if b.form == nil {
+			if err := parseForm(b.req); err != nil {
+				return nil, err
+			}
+			b.form = b.req.PostForm
+		}
+		return b.form[name], nil
+	}
+}
+
+// parseForm parses the form fields in req's body.
+func parseForm(req *http.Request) error {
+	var err error
+	if mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mt == "multipart/form-data" {
+		err = req.ParseMultipartForm(32 << 20)
+	} else {
+		err = req.ParseForm()
+	}
+	var mbe *http.MaxBytesError
+	if err != nil && !errors.As(err, &mbe) {
+		err = Errorf(http.StatusBadRequest, "bad form: %w", err)
+	}
+	if req.PostForm == nil {
+		req.PostForm = url.Values{}
+	}
+	return err
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// bindable reports whether setField can set a field of type t.
+func bindable(t reflect.Type) bool {
+	return settable(t) || (t.Kind() == reflect.Slice && settable(t.Elem()))
+}
+
+// settable reports whether setValue can set a value of type t.
+func settable(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+// setField sets v from vals: all of them for a slice, otherwise the
+// first.
+func setField(v reflect.Value, vals []string) error {
+	if v.Kind() == reflect.Slice && !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) && v.Type().Elem().Kind() != reflect.Uint8 {
+		s := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setValue(s.Index(i), val); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setValue(v, vals[0])
+}
+
+// setValue parses s into v.
+func setValue(v reflect.Value, s string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		v.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("can't set %s", v.Type())
+	}
+	return nil
+}
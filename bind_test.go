@@ -0,0 +1,108 @@
+package route
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindPage struct {
+	Page int `query:"page"`
+}
+
+type bindInput struct {
+	bindPage
+	ID    int       `path:"id"`
+	Email string    `form:"email,required"`
+	Tags  []string  `form:"tag"`
+	Ratio float64   `form:"ratio"`
+	OK    bool      `query:"ok"`
+	Start time.Time `form:"start"`
+	Other string
+}
+
+// bindList is a slice parsed from a single comma-separated value.
+type bindList []string
+
+func (l *bindList) UnmarshalText(b []byte) error {
+	*l = strings.Split(string(b), ",")
+	return nil
+}
+
+func formRequest(query, body string) *http.Request {
+	req := httptest.NewRequest("POST", "/users/7?"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestBind(t *testing.T) {
+	env := map[string]string{"id": "7"}
+	var in bindInput
+	err := Bind(formRequest("page=2&ok=true&email=wrong@x", "email=a@b&tag=x&tag=y&ratio=0.5&start=2024-03-01T10:00:00Z"), env, &in)
+	assert.Nil(t, err)
+	assert.Equal(t, bindInput{
+		bindPage: bindPage{2},
+		ID:       7,
+		Email:    "a@b",
+		Tags:     []string{"x", "y"},
+		Ratio:    0.5,
+		OK:       true,
+		Start:    time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}, in)
+
+	err = Bind(formRequest("", "tag=x"), env, &in)
+	assert.Equal(t, "missing form field email", err.Error())
+	assert.Equal(t, http.StatusBadRequest, defaultMapper.Status(err))
+
+	err = Bind(formRequest("page=two", "email=a@b"), env, &in)
+	assert.Equal(t, `invalid query parameter page "two"`, err.Error())
+	assert.Equal(t, http.StatusBadRequest, defaultMapper.Status(err))
+
+	err = Bind(formRequest("", "email=a@b"), map[string]string{"id": "x"}, &in)
+	assert.Equal(t, `invalid path variable id "x"`, err.Error())
+
+	assert.Panics(t, func() { Bind(formRequest("", ""), env, in) })
+}
+
+func TestBindFieldTypes(t *testing.T) {
+	var in struct {
+		Data []byte `query:"data"`
+	}
+	assert.Nil(t, Bind(httptest.NewRequest("GET", "/?data=abc", nil), nil, &in))
+	assert.Equal(t, []byte("abc"), in.Data)
+
+	// A slice type with its own UnmarshalText takes the first value whole.
+	var list struct {
+		IDs bindList `query:"ids"`
+	}
+	assert.Nil(t, Bind(httptest.NewRequest("GET", "/?ids=1,2&ids=3", nil), nil, &list))
+	assert.Equal(t, bindList{"1", "2"}, list.IDs)
+
+	// Unsupported types panic whether or not a value is given.
+	var bad struct {
+		P *int `query:"p"`
+	}
+	assert.Panics(t, func() { Bind(httptest.NewRequest("GET", "/", nil), nil, &bad) })
+	assert.Panics(t, func() { Bind(httptest.NewRequest("GET", "/?p=1", nil), nil, &bad) })
+}
+
+func TestBindMultipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("email", "a@b")
+	mw.Close()
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var in struct {
+		Email string `form:"email"`
+	}
+	assert.Nil(t, Bind(req, nil, &in))
+	assert.Equal(t, "a@b", in.Email)
+}
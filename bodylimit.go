@@ -0,0 +1,51 @@
+package route
+
+import "net/http"
+
+// MaxBodySize limits the size of request bodies for routes at or
+// beneath the current point to n bytes, so different subtrees can
+// accept different amounts:
+//
+//     r.MaxBodySize(1 << 20)                   // 1MB for JSON APIs.
+//     r.Route("/upload").MaxBodySize(100 << 20) // 100MB for uploads.
+//
+// A limit set on a subtree overrides one set nearer the root, and a
+// negative n removes the limit.
+//
+// Requests declaring a larger Content-Length are rejected before the
+// handler runs; otherwise the body is wrapped with http.MaxBytesReader,
+// whose reads fail with an *http.MaxBytesError once the limit is
+// exceeded.  Either way the error is reported through the route's
+// error handler (see ErrorHandler), which by default responds with 413
+// Request Entity Too Large, so a FuncErr handler can simply return the
+// error from reading the body.
+func (r *Router) MaxBodySize(n int64) {
+	defer r.lock()()
+	r.mustNotBeFrozen()
+	r.maxBody = n
+	r.root().bodyLimits.Store(true)
+}
+
+// bodyLimit returns the body size limit for requests to r, inherited
+// from its nearest ancestor that has one, or 0 for none.
+func (r *Router) bodyLimit() int64 {
+	for n := r; n != nil; n = n.parent {
+		if n.maxBody != 0 {
+			return max(n.maxBody, 0)
+		}
+	}
+	return 0
+}
+
+// limitBody applies limit to req's body for a request to n.  It
+// reports false if the request has already been rejected.
+func limitBody(w http.ResponseWriter, req *http.Request, n *Router, limit int64) bool {
+	if req.ContentLength > limit {
+		n.handleError(w, req, &http.MaxBytesError{Limit: limit})
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = http.MaxBytesReader(w, req.Body, limit)
+	}
+	return true
+}
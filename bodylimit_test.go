@@ -0,0 +1,66 @@
+package route
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readBody(w http.ResponseWriter, r *http.Request, env map[string]string) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	w.Write(body)
+	return nil
+}
+
+// post sends body with no declared length, so only reading it reveals
+// its size.
+func post(h http.Handler, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", path, io.NopCloser(strings.NewReader(body)))
+	req.ContentLength = -1
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestMaxBodySize(t *testing.T) {
+	r := &Router{}
+	r.MaxBodySize(4)
+	r.Route("/api").FuncErr(readBody)
+	r.Route("/upload").MaxBodySize(8)
+	r.Route("/upload").FuncErr(readBody)
+	r.Route("/raw").MaxBodySize(-1)
+	r.Route("/raw").FuncErr(readBody)
+
+	assert.Equal(t, "abcd", post(r, "/api", "abcd").Body.String())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(r, "/api", "abcde").Code)
+	assert.Equal(t, "abcdefgh", post(r, "/upload", "abcdefgh").Body.String())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(r, "/upload", "abcdefghi").Code)
+	assert.Equal(t, "abcdefghij", post(r, "/raw", "abcdefghij").Body.String())
+
+	// A declared length over the limit is rejected up front.
+	called := false
+	r.Route("/early").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		called = true
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/early", strings.NewReader("abcdef")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.False(t, called)
+}
+
+func TestMaxBodySizeProblem(t *testing.T) {
+	r := &Router{}
+	r.MaxBodySize(2)
+	r.ProblemJSON(nil)
+	r.Route("/api").FuncErr(readBody)
+	w := post(r, "/api", "abc")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
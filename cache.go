@@ -0,0 +1,221 @@
+package route
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a response held by a CacheStore.
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Stored is when the response was generated.
+	Stored time.Time
+	// Vary lists the request headers the response varies by, from
+	// its Vary header.
+	Vary []string
+}
+
+// CacheStore stores responses for Cache.  Implementations backed by
+// Redis, memcached and the like can be shared between servers.
+type CacheStore interface {
+	// Get returns the response stored under key, if it hasn't
+	// expired.
+	Get(key string) (*CachedResponse, bool)
+	// Set stores resp under key for ttl.
+	Set(key string, resp *CachedResponse, ttl time.Duration)
+}
+
+// MemoryCache is an in-memory CacheStore, evicting the least recently
+// used entries once full.
+type MemoryCache struct {
+	mu      sync.Mutex
+	max     int
+	lru     *list.List // of *memoryEntry, most recent first
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	resp    *CachedResponse
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache holding up to max entries.
+func NewMemoryCache(max int) *MemoryCache {
+	return &MemoryCache{max: max, lru: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el := c.entries[key]
+	if el == nil {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if !timeNow().Before(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e.resp, true
+}
+
+func (c *MemoryCache) Set(key string, resp *CachedResponse, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &memoryEntry{key, resp, timeNow().Add(ttl)}
+	if el := c.entries[key]; el != nil {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.max {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*memoryEntry).key)
+	}
+}
+
+// Cache caches successful responses to GET requests at or beneath the
+// current point for ttl, in a MemoryCache of 1000 entries shared by
+// the tree.  It returns the router, to allow chaining:
+//
+//     r.Route("/popular").Cache(5 * time.Minute).FuncE(popular)
+//
+// See CacheIn for details.
+func (r *Router) Cache(ttl time.Duration) *Router {
+	unlock := r.lock()
+	root := r.root()
+	if root.cache == nil {
+		root.cache = NewMemoryCache(1000)
+	}
+	store := root.cache
+	unlock()
+	return r.CacheIn(store, ttl)
+}
+
+// CacheIn is like Cache, but uses the given store.
+//
+// Responses are keyed by the request's host, path and query, along
+// with the values of any request headers named by the response's Vary
+// header, including names added to it before the cache was reached,
+// as by Compress or Accept.  Only 200 responses are cached, and not
+// those with a Set-Cookie header, a Cache-Control header forbidding
+// shared caching or a Vary of "*", nor those to requests with an
+// Authorization header.  Responses served from the cache have an Age
+// header.
+func (r *Router) CacheIn(store CacheStore, ttl time.Duration) *Router {
+	return r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if req.Method != http.MethodGet || req.Header.Get("Authorization") != "" {
+				next(w, req, env)
+				return
+			}
+			base := "GET " + req.Host + req.URL.RequestURI()
+			if resp, ok := store.Get(base); ok {
+				key := base
+				if len(resp.Vary) > 0 {
+					key = varyKey(base, resp.Vary, req)
+					resp, ok = store.Get(key)
+				}
+				if ok {
+					serveCached(w, resp)
+					return
+				}
+			}
+
+			buf := newResponseBuffer()
+			next(buf, req, env)
+			buf.writeTo(w)
+			vary, ok := varyNames(w.Header())
+			if !ok || !cacheable(buf) {
+				return
+			}
+			resp := &CachedResponse{
+				Status: buf.code,
+				Header: buf.header.Clone(),
+				Body:   append([]byte(nil), buf.body.Bytes()...),
+				Stored: timeNow(),
+				Vary:   vary,
+			}
+			if len(resp.Vary) > 0 {
+				// Record the Vary list under the base key, and the
+				// response under a key including the varying headers.
+				store.Set(base, &CachedResponse{Vary: resp.Vary}, ttl)
+				store.Set(varyKey(base, resp.Vary, req), resp, ttl)
+			} else {
+				store.Set(base, resp, ttl)
+			}
+		}
+	})
+}
+
+// varyNames returns the request header names listed in h's Vary
+// header, without duplicates, or false if it lists "*".
+func varyNames(h http.Header) ([]string, bool) {
+	var names []string
+	for _, v := range h.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "*" {
+				return nil, false
+			}
+			found := false
+			for _, n := range names {
+				found = found || n == name
+			}
+			if name != "" && !found {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, true
+}
+
+// varyKey extends a cache key with the values of the request headers
+// named in vary.
+func varyKey(base string, vary []string, req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, name := range vary {
+		b.WriteString("\x00")
+		b.WriteString(name)
+		b.WriteString(":")
+		b.WriteString(strings.Join(req.Header.Values(name), ","))
+	}
+	return b.String()
+}
+
+// cacheable reports whether a buffered response may be cached.
+func cacheable(buf *responseBuffer) bool {
+	if buf.code != http.StatusOK && buf.code != 0 {
+		return false
+	}
+	if buf.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := strings.ToLower(buf.header.Get("Cache-Control"))
+	for _, d := range []string{"no-store", "no-cache", "private"} {
+		if strings.Contains(cc, d) {
+			return false
+		}
+	}
+	return true
+}
+
+func serveCached(w http.ResponseWriter, resp *CachedResponse) {
+	copyHeader(w.Header(), resp.Header)
+	age := int(timeNow().Sub(resp.Stored) / time.Second)
+	w.Header().Set("Age", strconv.Itoa(age))
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
@@ -0,0 +1,93 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	now := fakeClock(t)
+	calls := 0
+	counter := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(strconv.Itoa(calls)))
+	}
+	r := &Router{}
+	store := NewMemoryCache(10)
+	r.Route("/popular").CacheIn(store, time.Minute).Func(counter)
+	r.Route("/lang").CacheIn(store, time.Minute).Func(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept-Language")
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	})
+	r.Route("/private").CacheIn(store, time.Minute).Func(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private")
+		counter(w, r)
+	})
+
+	assert.Equal(t, "1", get(r, "/popular").Body.String())
+	*now = now.Add(30 * time.Second)
+	w := get(r, "/popular")
+	assert.Equal(t, "1", w.Body.String())
+	assert.Equal(t, "30", w.Header().Get("Age"))
+	assert.Equal(t, "2", get(r, "/popular?page=2").Body.String())
+	assert.Equal(t, "3", do(r, "POST", "/popular").Body.String())
+
+	*now = now.Add(time.Minute)
+	assert.Equal(t, "4", get(r, "/popular").Body.String())
+
+	assert.Equal(t, "en", withHeader(r, "/lang", "Accept-Language", "en").Body.String())
+	assert.Equal(t, "fr", withHeader(r, "/lang", "Accept-Language", "fr").Body.String())
+	w = withHeader(r, "/lang", "Accept-Language", "en")
+	assert.Equal(t, "en", w.Body.String())
+	assert.Equal(t, "0", w.Header().Get("Age"))
+
+	assert.Equal(t, "5", get(r, "/private").Body.String())
+	assert.Equal(t, "6", get(r, "/private").Body.String())
+}
+
+func TestCacheKey(t *testing.T) {
+	fakeClock(t)
+	r := &Router{}
+	r.Route("/host").Cache(time.Minute).Func(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	})
+	doc := r.Route("/doc").Cache(time.Minute)
+	doc.Header("X-Format", "json").Func(writeString("json"))
+	doc.Func(writeString("text"))
+
+	req := httptest.NewRequest("GET", "/host", nil)
+	req.Host = "a.example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "a.example.com", w.Body.String())
+	req.Host = "b.example.com"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "b.example.com", w.Body.String())
+
+	// The Vary header added by the router is part of the key.
+	assert.Equal(t, "json", withHeader(r, "/doc", "X-Format", "json").Body.String())
+	w = withHeader(r, "/doc", "", "")
+	assert.Equal(t, "text", w.Body.String())
+	assert.Equal(t, "X-Format", w.Header().Get("Vary"))
+	assert.Equal(t, "json", withHeader(r, "/doc", "X-Format", "json").Body.String())
+}
+
+func TestMemoryCacheLRU(t *testing.T) {
+	fakeClock(t)
+	c := NewMemoryCache(2)
+	c.Set("a", &CachedResponse{Status: 1}, time.Minute)
+	c.Set("b", &CachedResponse{Status: 2}, time.Minute)
+	c.Get("a")
+	c.Set("c", &CachedResponse{Status: 3}, time.Minute)
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	resp, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, resp.Status)
+}
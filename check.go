@@ -0,0 +1,89 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Check analyzes the tree beneath r for likely mistakes, returning an
+// error that lists each problem found along with its pattern, or nil.
+// The problems detected are:
+//
+// - the same name given to more than one route, which makes reverse
+// routing ambiguous;
+//
+// - routes registered beneath a "*" fallback, which are unreachable
+// since the fallback consumes the rest of the path;
+//
+// - subtrees with no handlers at all, usually left behind by a Route
+// call that was never followed by a Func.
+//
+// A fallback doesn't shadow the routes beside it, whenever they were
+// registered: lookup tries static components, then variables, then the
+// fallback, backtracking when a branch has no handler, so the fallback
+// only gets paths nothing else matches.  Variables likewise don't
+// shadow static routes.  Check therefore doesn't report either.
+//
+// The returned error wraps one error per problem; see errors.Join.
+func (r *Router) Check() error {
+	defer r.rlock()()
+	var errs []error
+	problem := func(n *Router, format string, args ...interface{}) {
+		pattern := n.pattern
+		if pattern == "" {
+			pattern = "/"
+		}
+		errs = append(errs, fmt.Errorf("%s: %s", pattern, fmt.Sprintf(format, args...)))
+	}
+
+	names := map[string]string{}
+	r.walk(func(n *Router) error {
+		if n.name == "" {
+			return nil
+		}
+		if p, ok := names[n.name]; ok {
+			problem(n, "name %q already used by %s", n.name, p)
+		} else {
+			names[n.name] = n.pattern
+		}
+		return nil
+	})
+
+	var visit func(n *Router)
+	visit = func(n *Router) {
+		if n.fallbackRouter != nil {
+			f := n.fallbackRouter
+			if len(f.matchers) > 0 || f.varRouter != nil || f.fallbackRouter != nil {
+				problem(f, "routes beneath a fallback are unreachable")
+			}
+		}
+		if n != r && !n.hasHandlers() {
+			problem(n, "no handlers in subtree")
+			return
+		}
+		for _, k := range n.staticKeys() {
+			visit(n.matchers[k])
+		}
+		if n.varRouter != nil {
+			visit(n.varRouter)
+		}
+		if n.fallbackRouter != nil {
+			visit(n.fallbackRouter)
+		}
+	}
+	visit(r)
+
+	return errors.Join(errs...)
+}
+
+// hasHandlers reports whether any node beneath r, including r itself,
+// has a handler.
+func (r *Router) hasHandlers() bool {
+	found := errors.New("found")
+	return r.walk(func(n *Router) error {
+		if n.handler != nil {
+			return found
+		}
+		return nil
+	}) != nil
+}
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckClean(t *testing.T) {
+	r := &Router{}
+	r.Route("/").FuncE(F1)
+	r.Route("/users/:id").Name("user").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+	assert.Nil(t, r.Check())
+	assert.Nil(t, (&Router{}).Check())
+
+	// A fallback registered before the routes beside it doesn't shadow
+	// them.
+	r = &Router{}
+	r.Route("/files/*").Func(writeString("fallback"))
+	r.Route("/files/:id").Func(writeString("var"))
+	r.Route("/files/readme").Func(writeString("static"))
+	assert.Nil(t, r.Check())
+	assert.Equal(t, "static", get(r, "/files/readme").Body.String())
+	assert.Equal(t, "var", get(r, "/files/x").Body.String())
+	assert.Equal(t, "fallback", get(r, "/files/x/y").Body.String())
+}
+
+func TestCheckProblems(t *testing.T) {
+	r := &Router{}
+	r.Route("/a").Name("dup").FuncE(F1)
+	r.Route("/b").FuncE(F1)
+	// Name rejects duplicates, but Swap can still bring them in.
+	r.Route("/b").name = "dup"
+	static := r.Route("/static/*")
+	static.FuncE(F1)
+	static.Route("never").FuncE(F1)
+	r.Route("/empty/sub")
+
+	err := r.Check()
+	assert.NotNil(t, err)
+	assert.Equal(t, `/b: name "dup" already used by /a
+/empty: no handlers in subtree
+/static/*: routes beneath a fallback are unreachable`, err.Error())
+}
@@ -0,0 +1,107 @@
+package route
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// trustedKey is the context key under which ServeHTTP records the
+// Router's TrustedProxies.
+type trustedKey struct{}
+
+func withTrustedProxies(req *http.Request, trusted []netip.Prefix) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), trustedKey{}, trusted))
+}
+
+// ClientIP returns the address of the client that made req, for rate
+// limiting, logging and access control.  It is the address of the
+// immediate peer unless that is one of the TrustedProxies of the
+// Router serving req, in which case the Forwarded (RFC 7239) or, in
+// its absence, X-Forwarded-For header is read from right to left for
+// the first address that isn't a trusted proxy.  Headers from
+// untrusted peers are ignored, as they are trivially forged.
+//
+// It returns the zero Addr if no address can be determined, including
+// when the headers name a hop that isn't an address, like "unknown",
+// before reaching an untrusted one.
+func ClientIP(req *http.Request) netip.Addr {
+	trusted, _ := req.Context().Value(trustedKey{}).([]netip.Prefix)
+	return clientIP(req, trusted)
+}
+
+// clientIP is ClientIP with an explicit list of trusted proxies.
+func clientIP(req *http.Request, trusted []netip.Prefix) netip.Addr {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	addr = addr.Unmap()
+	if !contains(trusted, addr) {
+		return addr
+	}
+	hops := forwardedHops(req.Header)
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			return netip.Addr{}
+		}
+		addr = hop.Unmap()
+		if !contains(trusted, addr) {
+			break
+		}
+	}
+	return addr
+}
+
+// forwardedHops returns the client addresses listed by the Forwarded
+// header, or the X-Forwarded-For header if there is none, in order.
+// Addresses that can't be parsed, like "unknown", are returned as is.
+func forwardedHops(h http.Header) []string {
+	var hops []string
+	if fwd := h.Values("Forwarded"); len(fwd) > 0 {
+		for _, elem := range strings.Split(strings.Join(fwd, ","), ",") {
+			hop := ""
+			for _, pair := range strings.Split(elem, ";") {
+				k, v, _ := strings.Cut(strings.TrimSpace(pair), "=")
+				if strings.EqualFold(k, "for") {
+					hop = strings.Trim(v, `"`)
+				}
+			}
+			// Strip any port, as in "[2001:db8::1]:4711" or "192.0.2.1:80".
+			if strings.HasPrefix(hop, "[") {
+				if i := strings.IndexByte(hop, ']'); i >= 0 {
+					hop = hop[1:i]
+				}
+			} else if i := strings.IndexByte(hop, ':'); i >= 0 && strings.Count(hop, ":") == 1 {
+				hop = hop[:i]
+			}
+			hops = append(hops, hop)
+		}
+		return hops
+	}
+	xff := h.Values("X-Forwarded-For")
+	if len(xff) == 0 {
+		return nil
+	}
+	for _, hop := range strings.Split(strings.Join(xff, ","), ",") {
+		hops = append(hops, strings.TrimSpace(hop))
+	}
+	return hops
+}
+
+// contains reports whether addr is in any of prefixes.
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	var got netip.Addr
+	r := &Router{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+	r.Route("/").Func(func(w http.ResponseWriter, req *http.Request) {
+		got = ClientIP(req)
+	})
+	ip := func(remote string, headers ...string) string {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = remote
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Add(headers[i], headers[i+1])
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		return got.String()
+	}
+
+	assert.Equal(t, "1.2.3.4", ip("1.2.3.4:5"))
+	assert.Equal(t, "1.2.3.4", ip("1.2.3.4:5", "X-Forwarded-For", "6.6.6.6"))
+	assert.Equal(t, "6.6.6.6", ip("10.0.0.1:5", "X-Forwarded-For", "6.6.6.6"))
+	assert.Equal(t, "7.7.7.7", ip("10.0.0.1:5", "X-Forwarded-For", "6.6.6.6, 7.7.7.7, 10.0.0.2"))
+	assert.Equal(t, "7.7.7.7", ip("10.0.0.1:5", "X-Forwarded-For", "6.6.6.6", "X-Forwarded-For", "7.7.7.7"))
+	assert.Equal(t, "2001:db8::17", ip("10.0.0.1:5",
+		"Forwarded", `for=192.0.2.60;proto=http, for="[2001:db8::17]:4711"`,
+		"X-Forwarded-For", "6.6.6.6"))
+	assert.Equal(t, "192.0.2.60", ip("10.0.0.1:5", "Forwarded", `for=192.0.2.60:80;by=10.0.0.1`))
+	// An unparseable hop leaves the client unknown.
+	assert.Equal(t, "invalid IP", ip("10.0.0.1:5", "Forwarded", `for=unknown, for=10.0.0.2`))
+	assert.Equal(t, "invalid IP", ip("10.0.0.1:5", "X-Forwarded-For", "6.6.6.6, , 10.0.0.2"))
+	// A trusted proxy forwarding nothing is the client itself.
+	assert.Equal(t, "10.0.0.1", ip("10.0.0.1:5"))
+	assert.Equal(t, "invalid IP", ip("nonsense"))
+
+	// Without TrustedProxies, headers are ignored.
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.1:5"
+	req.Header.Set("X-Forwarded-For", "6.6.6.6")
+	assert.Equal(t, "10.0.0.1", ClientIP(req).String())
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/evmar/route"
+)
+
+// genRoute is a route along with the Go names generated for it.
+type genRoute struct {
+	route.LoadRoute
+	// ident is the Go identifier the route's declarations are based on.
+	ident string
+	vars  []genVar
+}
+
+// genVar is a variable captured by a route.
+type genVar struct {
+	key   string // key in the route's env, e.g. "id", or "*"
+	field string // field in the params struct, e.g. "ID"
+	arg   string // argument to the URL builder, e.g. "id"
+}
+
+// initialisms are written in all caps in generated identifiers.
+var initialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "json": true,
+	"uri": true, "url": true, "uuid": true,
+}
+
+// exportName converts a name like "users.show" or "post_id" to an
+// exported Go identifier like "UsersShow" or "PostID".
+func exportName(name string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(name, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	}) {
+		if initialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+		} else {
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	s := b.String()
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		s = "R" + s
+	}
+	return s
+}
+
+// argName converts an exported identifier to a name usable as a
+// function argument, avoiding keywords and the packages imported by
+// the generated code.
+func argName(field string) string {
+	n := 0
+	for n < len(field) && unicode.IsUpper(rune(field[n])) {
+		n++
+	}
+	if n > 1 && n < len(field) {
+		n-- // "IDValue" => "idValue"
+	}
+	s := strings.ToLower(field[:n]) + field[n:]
+	switch {
+	case token.IsKeyword(s), s == "url", s == "http", s == "route":
+		s += "_"
+	}
+	return s
+}
+
+// generate returns the Go bindings for the routes file src, named
+// srcName, as package pkg.
+func generate(src, srcName, pkg string) ([]byte, error) {
+	routes, err := route.ReadRoutes(src)
+	if err != nil {
+		return nil, err
+	}
+	var gen []genRoute
+	seen := map[string]string{}
+	for _, r := range routes {
+		g := genRoute{LoadRoute: r}
+		if r.Name != "" {
+			g.ident = exportName(r.Name)
+		} else {
+			g.ident = exportName(r.Handler)
+		}
+		if prev, ok := seen[g.ident]; ok {
+			return nil, fmt.Errorf("%s and %s both generate %s; give one a name with \"as\"", prev, r.Path, g.ident)
+		}
+		seen[g.ident] = r.Path
+		for _, part := range strings.Split(r.Path[1:], "/") {
+			switch {
+			case part == "*":
+				g.vars = append(g.vars, genVar{key: "*", field: "Path", arg: "path"})
+			case strings.HasPrefix(part, ":"):
+				field := exportName(part[1:])
+				g.vars = append(g.vars, genVar{key: part[1:], field: field, arg: argName(field)})
+			}
+		}
+		gen = append(gen, g)
+	}
+
+	var b bytes.Buffer
+	base := filepath.Base(srcName)
+	fmt.Fprintf(&b, "// Code generated by routegen from %s; DO NOT EDIT.\n\n", base)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\t\"net/http\"\n")
+	if needsEscape(gen) {
+		b.WriteString("\t\"net/url\"\n")
+	}
+	b.WriteString("\n\t\"github.com/evmar/route\"\n)\n")
+
+	for _, g := range gen {
+		desc := describe(g.LoadRoute)
+		if len(g.vars) > 0 {
+			fmt.Fprintf(&b, "\n// %sParams holds the variables captured by %s.\n", g.ident, desc)
+			fmt.Fprintf(&b, "type %sParams struct {\n", g.ident)
+			for _, v := range g.vars {
+				fmt.Fprintf(&b, "\t%s string\n", v.field)
+			}
+			b.WriteString("}\n")
+		}
+		var args []string
+		for _, v := range g.vars {
+			args = append(args, v.arg+" string")
+		}
+		fmt.Fprintf(&b, "\n// %sURL returns the path for %s.\n", g.ident, desc)
+		fmt.Fprintf(&b, "func %sURL(%s) string {\n\treturn %s\n}\n", g.ident, strings.Join(args, ", "), urlExpr(g))
+	}
+
+	fmt.Fprintf(&b, "\n// Handlers is implemented by the handlers for the routes in %s.\n", base)
+	b.WriteString("type Handlers interface {\n")
+	for _, g := range gen {
+		fmt.Fprintf(&b, "\t%s(w http.ResponseWriter, r *http.Request", g.ident)
+		if len(g.vars) > 0 {
+			fmt.Fprintf(&b, ", p %sParams", g.ident)
+		}
+		b.WriteString(")\n")
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(&b, "\n// Register registers h for the routes in %s on r.\n", base)
+	b.WriteString("func Register(r *route.Router, h Handlers) {\n")
+	for _, g := range gen {
+		fmt.Fprintf(&b, "\tr.Route(%q)", g.Path)
+		for _, m := range g.Methods {
+			fmt.Fprintf(&b, ".Method(%q)", m)
+		}
+		if g.Name != "" {
+			fmt.Fprintf(&b, ".Name(%q)", g.Name)
+		}
+		b.WriteString(".FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {\n")
+		fmt.Fprintf(&b, "\t\th.%s(w, req", g.ident)
+		if len(g.vars) > 0 {
+			var fields []string
+			for _, v := range g.vars {
+				fields = append(fields, fmt.Sprintf("%s: env[%q]", v.field, v.key))
+			}
+			fmt.Fprintf(&b, ", %sParams{%s}", g.ident, strings.Join(fields, ", "))
+		}
+		b.WriteString(")\n\t})\n")
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// describe returns a description of r for doc comments, like
+// "GET /users/:id".
+func describe(r route.LoadRoute) string {
+	if len(r.Methods) == 0 {
+		return r.Path
+	}
+	return strings.Join(r.Methods, ", ") + " " + r.Path
+}
+
+// needsEscape reports whether any route has a variable, so that the
+// generated URL builders use net/url.
+func needsEscape(gen []genRoute) bool {
+	for _, g := range gen {
+		for _, v := range g.vars {
+			if v.key != "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// urlExpr returns a Go expression building the path for g from the
+// arguments of its URL builder, mirroring route.Router.URL.
+func urlExpr(g genRoute) string {
+	if g.Path == "/" {
+		return `"/"`
+	}
+	var terms []string
+	lit := ""
+	vars := g.vars
+	for _, part := range strings.Split(g.Path[1:], "/") {
+		lit += "/"
+		if part != "*" && !strings.HasPrefix(part, ":") {
+			lit += part
+			continue
+		}
+		terms = append(terms, strconv.Quote(lit))
+		lit = ""
+		v := vars[0]
+		vars = vars[1:]
+		if v.key == "*" {
+			terms = append(terms, v.arg)
+		} else {
+			terms = append(terms, "url.PathEscape("+v.arg+")")
+		}
+	}
+	if lit != "" {
+		terms = append(terms, strconv.Quote(lit))
+	}
+	return strings.Join(terms, " + ")
+}
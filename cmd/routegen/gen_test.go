@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportName(t *testing.T) {
+	assert.Equal(t, "UsersShow", exportName("users.show"))
+	assert.Equal(t, "PostID", exportName("post_id"))
+	assert.Equal(t, "ID", exportName("id"))
+	assert.Equal(t, "R404", exportName("404"))
+	assert.Equal(t, "id", argName("ID"))
+	assert.Equal(t, "postID", argName("PostID"))
+	assert.Equal(t, "idValue", argName("IDValue"))
+	assert.Equal(t, "type_", argName("Type"))
+	assert.Equal(t, "url_", argName("URL"))
+}
+
+func TestGenerate(t *testing.T) {
+	code, err := generate(`
+GET  /                           home
+GET  /users/:id/posts/:post_id   posts.show
+POST /users/:id                  users.update as user.update
+ANY  /static/*                   static
+`, "testdata/routes.txt", "app")
+	assert.Nil(t, err)
+	src := string(code)
+	assert.Contains(t, src, "// Code generated by routegen from routes.txt; DO NOT EDIT.")
+	assert.Contains(t, src, "package app\n")
+	assert.Contains(t, src, "type PostsShowParams struct {\n\tID     string\n\tPostID string\n}")
+	assert.Contains(t, src, `func HomeURL() string {
+	return "/"
+}`)
+	assert.Contains(t, src, `func PostsShowURL(id string, postID string) string {
+	return "/users/" + url.PathEscape(id) + "/posts/" + url.PathEscape(postID)
+}`)
+	assert.Contains(t, src, `func StaticURL(path string) string {
+	return "/static/" + path
+}`)
+	assert.Contains(t, src, "\tUserUpdate(w http.ResponseWriter, r *http.Request, p UserUpdateParams)\n")
+	assert.Contains(t, src, "\tHome(w http.ResponseWriter, r *http.Request)\n")
+	assert.Contains(t, src, `r.Route("/users/:id").Method("POST").Name("user.update").FuncE(`)
+	assert.Contains(t, src, `h.PostsShow(w, req, PostsShowParams{ID: env["id"], PostID: env["post_id"]})`)
+	assert.Contains(t, src, `h.Static(w, req, StaticParams{Path: env["*"]})`)
+}
+
+func TestGenerateMethods(t *testing.T) {
+	code, err := generate(`
+GET  /users/:id  users.show    as user.show
+POST /users/:id  users.update  as user.update
+`, "routes.txt", "app")
+	assert.Nil(t, err)
+	src := string(code)
+	assert.Contains(t, src, `r.Route("/users/:id").Method("GET").Name("user.show").FuncE(`)
+	assert.Contains(t, src, `r.Route("/users/:id").Method("POST").Name("user.update").FuncE(`)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := generate("GET /x", "routes.txt", "app")
+	assert.NotNil(t, err)
+	_, err = generate("GET /a users.show\nPOST /b users.show", "routes.txt", "app")
+	assert.NotNil(t, err)
+}
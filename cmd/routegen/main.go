@@ -0,0 +1,61 @@
+// Command routegen generates typed Go bindings for a routes file, in
+// the format read by route.ParseRoutes.
+//
+// For each route it generates a struct holding the route's variables,
+// a function building the route's URL, and a method on a Handlers
+// interface; a Register function then registers an implementation of
+// Handlers on a route.Router.  Given the line
+//
+//     GET /users/:id users.show
+//
+// routegen generates, in outline:
+//
+//     type UsersShowParams struct{ ID string }
+//     func UsersShowURL(id string) string
+//     type Handlers interface {
+//         UsersShow(w http.ResponseWriter, r *http.Request, p UsersShowParams)
+//         ...
+//     }
+//     func Register(r *route.Router, h Handlers)
+//
+// so a route missing a handler, or a URL built with the wrong
+// arguments, is a compile error.  Routes are identified by their
+// name, if they have one, and otherwise by their handler name.
+//
+// It is intended for use with go generate:
+//
+//     //go:generate go run github.com/evmar/route/cmd/routegen -in routes.txt -out routes_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "routes.txt", "routes file to read")
+	out := flag.String("out", "routes_gen.go", "Go file to write")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name for the generated code")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "routegen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	if pkg == "" {
+		return fmt.Errorf("no package name; pass -pkg or run from go generate")
+	}
+	src, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	code, err := generate(string(src), in, pkg)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	return os.WriteFile(out, code, 0666)
+}
@@ -0,0 +1,114 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Compile optimizes the tree beneath r for lookup.  Long chains of
+// nodes that each have a single static child and nothing else, as
+// created by routes like "/api/v1/admin/reports/daily", are compressed
+// so matching compares path components directly rather than
+// descending through a map per component.
+//
+// Compile is optional and only affects performance.  It is best called
+// once all routes are registered, since any later change to a node
+// discards the compression of the chains through it.
+func (r *Router) Compile() {
+	defer r.lock()()
+	r.compile()
+}
+
+func (r *Router) compile() {
+	r.walk(func(n *Router) error {
+		n.skip, n.skipTo = nil, nil
+		var skip []string
+		cur := n
+		for cur.plainChain() {
+			for k, m := range cur.matchers {
+				skip = append(skip, k)
+				cur = m
+			}
+		}
+		if len(skip) >= 2 {
+			n.skip, n.skipTo = skip, cur
+		}
+		return nil
+	})
+}
+
+// plainChain reports whether r is a link in a compressible chain: it
+// has exactly one static child and nothing else that affects matching.
+func (r *Router) plainChain() bool {
+	return len(r.matchers) == 1 && r.handler == nil && r.varRouter == nil &&
+		r.fallbackRouter == nil && len(r.variants) == 0 && len(r.middleware) == 0
+}
+
+// invalidate discards any compression computed by Compile for the
+// chains through r, which must be called before r changes.  It panics
+// if r is frozen.
+func (r *Router) invalidate() {
+	if r.isFrozen() {
+		panic(ErrFrozen.Error())
+	}
+	for n := r; n != nil; n = n.parent {
+		n.skip, n.skipTo = nil, nil
+	}
+}
+
+// ErrFrozen is returned when modifying a router after Freeze.
+var ErrFrozen = errors.New("route: router is frozen")
+
+// Freeze finishes construction of the tree beneath r: it validates the
+// tree with Check, optimizes it as with Compile, and makes it
+// immutable.  Afterwards, attempts to modify the tree (RouteE, TryFunc,
+// Merge) return ErrFrozen, and those without an error result (Route,
+// Func, Use, Name, SetMeta and so on) panic.
+//
+// Since nothing can change it, a frozen router serves requests without
+// taking the tree's lock.  Freeze returns r as an http.Handler to make
+// the build-then-serve lifecycle explicit:
+//
+//     h, err := r.Freeze()
+//     if err != nil {
+//         log.Fatal(err)
+//     }
+//     http.ListenAndServe(":8080", h)
+//
+// If Check fails, the router is left unfrozen.
+func (r *Router) Freeze() (http.Handler, error) {
+	if err := r.Check(); err != nil {
+		return nil, err
+	}
+	unlock, err := r.lockE()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	r.compile()
+	r.walk(func(n *Router) error {
+		n.sortedKeys = nil
+		n.sortedKeys = n.staticKeys()
+		return nil
+	})
+	r.frozen.Store(true)
+	return r, nil
+}
+
+// mustNotBeFrozen panics with ErrFrozen if r's tree is frozen, for
+// changes to it without an error result.
+func (r *Router) mustNotBeFrozen() {
+	if r.isFrozen() {
+		panic(ErrFrozen.Error())
+	}
+}
+
+// isFrozen reports whether r or any of its ancestors has been frozen.
+func (r *Router) isFrozen() bool {
+	for n := r; n != nil; n = n.parent {
+		if n.frozen.Load() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package route
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	r := &Router{}
+	r.Route("/api/v1/admin/reports/daily").FuncE(F1)
+	r.Route("/api/v1/admin/reports/:id/raw").FuncE(F1)
+	r.Compile()
+
+	// The root chains through to "reports", which has two children.
+	assert.Equal(t, []string{"api", "v1", "admin", "reports"}, r.skip)
+	assert.NotNil(t, r.lookupPath("/api/v1/admin/reports/daily", map[string]string{}))
+	assert.NotNil(t, r.lookupPath("/api/v1/admin/reports/5/raw", map[string]string{}))
+	assert.Nil(t, r.lookupPath("/api/v2/admin/reports/daily", map[string]string{}))
+	assert.Nil(t, r.lookupPath("/api/v1", map[string]string{}))
+
+	// Changes within a chain discard it.
+	r.Route("/api/v1/users").FuncE(F1)
+	assert.Nil(t, r.skip)
+	assert.NotNil(t, r.lookupPath("/api/v1/users", nil))
+	assert.NotNil(t, r.lookupPath("/api/v1/admin/reports/daily", nil))
+
+	r.Compile()
+	assert.Equal(t, []string{"api", "v1"}, r.skip)
+	r.Route("/api").FuncE(F1)
+	assert.Nil(t, r.skip)
+	assert.NotNil(t, r.lookupPath("/api", nil))
+}
+
+func benchmarkRouter() *Router {
+	r := &Router{}
+	for i := 0; i < 1000; i++ {
+		r.Route(fmt.Sprintf("/api/v1/service%d/resource/list", i)).FuncE(F1)
+	}
+	r.Route("/api/v1/deep/chain/of/static/components/here").FuncE(F1)
+	return r
+}
+
+func BenchmarkLookup(b *testing.B) {
+	r := benchmarkRouter()
+	for i := 0; i < b.N; i++ {
+		r.lookupPath("/api/v1/deep/chain/of/static/components/here", nil)
+	}
+}
+
+func BenchmarkLookupCompiled(b *testing.B) {
+	r := benchmarkRouter()
+	r.Compile()
+	for i := 0; i < b.N; i++ {
+		r.lookupPath("/api/v1/deep/chain/of/static/components/here", nil)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/about").FuncE(F1)
+	h, err := r.Freeze()
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, get(h, "/nope").Code)
+
+	_, err = r.RouteE("/new")
+	assert.Equal(t, ErrFrozen, err)
+	_, err = r.matchers["users"].RouteE(":id/edit")
+	assert.Equal(t, ErrFrozen, err)
+	assert.Equal(t, ErrFrozen, r.matchers["about"].TryFuncE(F1))
+	assert.Equal(t, ErrFrozen, r.Merge(&Router{}, "/x"))
+	assert.Panics(t, func() { r.Route("/users") })
+	assert.Panics(t, func() { r.Use(tag("x")) })
+	about := r.matchers["about"]
+	assert.Panics(t, func() { about.Name("about") })
+	assert.Panics(t, func() { about.SetMeta("k", "v") })
+	assert.Panics(t, func() { about.NotFound(http.NotFound) })
+	assert.Panics(t, func() { about.MethodNotAllowed(nil) })
+	assert.Panics(t, func() { about.ErrorHandler(nil) })
+	assert.Panics(t, func() { about.MaxBodySize(1) })
+	assert.Panics(t, func() { about.Templates(template.New("")) })
+	assert.Equal(t, 0, len(about.Meta()))
+
+	assert.Equal(t, []string{"about", "users"}, r.sortedKeys)
+	assert.Equal(t, 2, len(r.Routes()))
+}
+
+func TestFreezeInvalid(t *testing.T) {
+	r := &Router{}
+	r.Route("/empty")
+	_, err := r.Freeze()
+	assert.NotNil(t, err)
+	assert.False(t, r.isFrozen())
+}
+
+func TestFreezeConcurrent(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(F1)
+	h, err := r.Freeze()
+	assert.Nil(t, err)
+	done := make(chan bool)
+	for i := 0; i < 4; i++ {
+		go func() {
+			for j := 0; j < 100; j++ {
+				get(h, "/users/5")
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+}
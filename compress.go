@@ -0,0 +1,201 @@
+package route
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Encoder is a content coding for Compress, like gzip.
+type Encoder struct {
+	// Name is the coding's name in Accept-Encoding and
+	// Content-Encoding headers, like "gzip" or "br".
+	Name string
+	// New returns a writer compressing to w.
+	New func(w io.Writer) io.WriteCloser
+}
+
+// Gzip is the gzip Encoder.
+var Gzip = Encoder{"gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }}
+
+// Compress is response compression middleware, negotiated by the
+// request's Accept-Encoding header.  Attach it to the subtrees that
+// benefit:
+//
+//     r.Route("/api").Use(route.Compress{}.Middleware())
+//
+// Brotli and other codings are supported through Encoders; for
+// example, with a third-party brotli package:
+//
+//     br := route.Encoder{Name: "br", New: func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }}
+//     r.Use(route.Compress{Encoders: []route.Encoder{br, route.Gzip}}.Middleware())
+//
+// Responses whose content type is already compressed, like images,
+// video and archives, that already have a Content-Encoding, or that
+// are partial (206) responses to range requests are sent as is.
+type Compress struct {
+	// Encoders lists the available codings, most preferred first.
+	// If nil, only Gzip is used.
+	Encoders []Encoder
+}
+
+// Middleware returns middleware applying c.
+func (c Compress) Middleware() Middleware {
+	encoders := c.Encoders
+	if encoders == nil {
+		encoders = []Encoder{Gzip}
+	}
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			enc := chooseEncoding(req.Header.Get("Accept-Encoding"), encoders)
+			if enc == nil || req.Method == http.MethodHead {
+				next(w, req, env)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, enc: enc}
+			defer cw.close()
+			next(cw, req, env)
+		}
+	}
+}
+
+// chooseEncoding returns the encoder the Accept-Encoding header
+// prefers, or nil if it prefers none to the identity coding.
+func chooseEncoding(header string, encoders []Encoder) *Encoder {
+	qs := map[string]float64{}
+	for _, s := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(s), ";")
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		if name != "" {
+			qs[strings.ToLower(name)] = q
+		}
+	}
+	var best *Encoder
+	bestQ := 0.0
+	for i, e := range encoders {
+		q, ok := qs[e.Name]
+		if !ok {
+			q = qs["*"]
+		}
+		if q > bestQ {
+			best, bestQ = &encoders[i], q
+		}
+	}
+	return best
+}
+
+// incompressible lists prefixes of media types that are already
+// compressed.
+var incompressible = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/zstd", "application/x-7z-compressed", "application/x-rar-compressed",
+	"application/pdf", "application/wasm",
+}
+
+func compressible(contentType string) bool {
+	if strings.HasPrefix(contentType, "image/svg") {
+		return true
+	}
+	for _, p := range incompressible {
+		if strings.HasPrefix(contentType, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// compressWriter compresses a response, if suitable, once its headers
+// and the start of its body are known.
+type compressWriter struct {
+	http.ResponseWriter
+	enc     *Encoder
+	code    int // status held back by WriteHeader, or 0
+	decided bool
+	w       io.WriteCloser // nil if not compressing
+}
+
+// WriteHeader holds the status back until the first Write, so that a
+// body without a Content-Type can be sniffed before deciding whether
+// to compress it, unless the headers already settle that.
+func (cw *compressWriter) WriteHeader(code int) {
+	if code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if cw.decided || cw.code != 0 {
+		return
+	}
+	cw.code = code
+	if cw.Header().Get("Content-Type") != "" || !compressibleStatus(code) {
+		cw.decide()
+	}
+}
+
+// compressibleStatus reports whether a response with the given status
+// has a body that may be compressed.
+func compressibleStatus(code int) bool {
+	return code != http.StatusNoContent && code != http.StatusNotModified &&
+		code != http.StatusPartialContent
+}
+
+// decide decides whether to compress the response and sends its
+// headers.
+func (cw *compressWriter) decide() {
+	cw.decided = true
+	if cw.code == 0 {
+		cw.code = http.StatusOK
+	}
+	h := cw.Header()
+	if compressibleStatus(cw.code) && h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", cw.enc.Name)
+		h.Del("Content-Length")
+		cw.w = cw.enc.New(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.code)
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		cw.decide()
+	}
+	if cw.w == nil {
+		return cw.ResponseWriter.Write(p)
+	}
+	return cw.w.Write(p)
+}
+
+// Flush flushes compressed data to the client, for streaming handlers.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide()
+	}
+	if f, ok := cw.w.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter { return cw.ResponseWriter }
+
+func (cw *compressWriter) close() {
+	if !cw.decided && cw.code != 0 {
+		// There was no body to compress.
+		cw.decided = true
+		cw.ResponseWriter.WriteHeader(cw.code)
+	}
+	if cw.w != nil {
+		cw.w.Close()
+	}
+}
@@ -0,0 +1,90 @@
+package route
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseEncoding(t *testing.T) {
+	br := Encoder{Name: "br"}
+	encs := []Encoder{br, Gzip}
+	name := func(header string) string {
+		if e := chooseEncoding(header, encs); e != nil {
+			return e.Name
+		}
+		return ""
+	}
+	assert.Equal(t, "br", name("gzip, br"))
+	assert.Equal(t, "gzip", name("gzip"))
+	assert.Equal(t, "gzip", name("br;q=0.5, gzip"))
+	assert.Equal(t, "br", name("*"))
+	assert.Equal(t, "gzip", name("br;q=0, *"))
+	assert.Equal(t, "", name(""))
+	assert.Equal(t, "", name("identity"))
+	assert.Equal(t, "", name("gzip;q=0"))
+}
+
+func TestCompress(t *testing.T) {
+	text := strings.Repeat("hello, world ", 100)
+	r := &Router{}
+	r.Use(Compress{}.Middleware())
+	r.Route("/text").Func(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(text))
+	})
+	r.Route("/png").Func(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte(text))
+	})
+	r.Route("/empty").Func(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Route("/status/:type").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		w.WriteHeader(http.StatusCreated)
+		if env["type"] == "png" {
+			w.Write([]byte("\x89PNG\r\n\x1a\n" + text))
+		} else if env["type"] == "text" {
+			w.Write([]byte(text))
+		}
+	})
+
+	w := withHeader(r, "/text", "Accept-Encoding", "gzip")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	zr, err := gzip.NewReader(w.Body)
+	assert.Nil(t, err)
+	body, _ := io.ReadAll(zr)
+	assert.Equal(t, text, string(body))
+
+	w = withHeader(r, "/text", "", "")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, text, w.Body.String())
+
+	w = withHeader(r, "/png", "Accept-Encoding", "gzip")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, text, w.Body.String())
+
+	w = withHeader(r, "/empty", "Accept-Encoding", "gzip")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, 0, w.Body.Len())
+
+	// The content type is sniffed even after an explicit WriteHeader.
+	w = withHeader(r, "/status/png", "Accept-Encoding", "gzip")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	w = withHeader(r, "/status/text", "Accept-Encoding", "gzip")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	w = withHeader(r, "/status/none", "Accept-Encoding", "gzip")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, 0, w.Body.Len())
+}
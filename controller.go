@@ -0,0 +1,96 @@
+package route
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+)
+
+// restActions lists the conventional REST routes.  Member actions are
+// beneath the collection's path and a variable holding the id, and
+// suffix, if set, is a further path component.
+var restActions = []struct {
+	name, method string
+	member       bool
+	suffix       string
+}{
+	{"Index", http.MethodGet, false, ""},
+	{"New", http.MethodGet, false, "new"},
+	{"Create", http.MethodPost, false, ""},
+	{"Show", http.MethodGet, true, ""},
+	{"Edit", http.MethodGet, true, "edit"},
+	{"Update", http.MethodPut, true, ""},
+	{"Update", http.MethodPatch, true, ""},
+	{"Delete", http.MethodDelete, true, ""},
+}
+
+// Controller registers the exported methods of c beneath path,
+// following REST conventions:
+//
+//     Index   GET    /users
+//     New     GET    /users/new
+//     Create  POST   /users
+//     Show    GET    /users/:id
+//     Edit    GET    /users/:id/edit
+//     Update  PUT    /users/:id      (and PATCH)
+//     Delete  DELETE /users/:id
+//
+// Each method must have the signature of either a HandlerE or an
+// http.HandlerFunc; member actions find the captured id in env["id"].
+// Methods c doesn't have are skipped, and other methods are ignored.
+//
+//     type Users struct{ db *DB }
+//     func (u *Users) Index(w http.ResponseWriter, r *http.Request) { ... }
+//     func (u *Users) Show(w http.ResponseWriter, r *http.Request, env map[string]string) { ... }
+//
+//     r.Controller("/users", &Users{db})
+//
+// Controller panics if c has none of the methods, if one has the wrong
+// signature, or if a route conflicts with an existing one.
+func (r *Router) Controller(path string, c interface{}) {
+	if err := r.ControllerE(path, c); err != nil {
+		log.Panic(err)
+	}
+}
+
+// ControllerE is like Controller, but returns an error rather than
+// panicking.  Either all of c's routes are registered or none are.
+func (r *Router) ControllerE(path string, c interface{}) error {
+	v := reflect.ValueOf(c)
+	tmp := &Router{}
+	found := false
+	for _, a := range restActions {
+		m := v.MethodByName(a.name)
+		if !m.IsValid() {
+			continue
+		}
+		found = true
+		var h HandlerE
+		switch f := m.Interface().(type) {
+		case func(http.ResponseWriter, *http.Request, map[string]string):
+			h = f
+		case func(http.ResponseWriter, *http.Request):
+			h = func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+				f(w, req)
+			}
+		default:
+			return fmt.Errorf("route: %T.%s has signature %s, want a handler", c, a.name, m.Type())
+		}
+		n := tmp
+		if a.member {
+			n = n.Route(":id")
+		}
+		if a.suffix != "" {
+			n = n.Route(a.suffix)
+		}
+		// tmp is not yet shared, so it is safe to skip locking.
+		if err := n.Method(a.method).setHandler(h, fmt.Sprintf("%T.%s", c, a.name)); err != nil {
+			return fmt.Errorf("route: %T.%s: %w", c, a.name, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("route: %T has no controller methods", c)
+	}
+	return r.Merge(tmp, path)
+}
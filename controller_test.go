@@ -0,0 +1,56 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userController struct{}
+
+func (*userController) Index(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("index"))
+}
+
+func (*userController) Show(w http.ResponseWriter, r *http.Request, env map[string]string) {
+	w.Write([]byte("show " + env["id"]))
+}
+
+func (*userController) Update(w http.ResponseWriter, r *http.Request, env map[string]string) {
+	w.Write([]byte(r.Method + " " + env["id"]))
+}
+
+// Unrelated methods are ignored.
+func (*userController) String() string { return "users" }
+
+type badController struct{}
+
+func (badController) Show(id int) {}
+
+func TestController(t *testing.T) {
+	r := &Router{}
+	r.Controller("/users", &userController{})
+
+	assert.Equal(t, "index", do(r, "GET", "/users").Body.String())
+	assert.Equal(t, "show 5", do(r, "GET", "/users/5").Body.String())
+	assert.Equal(t, "PUT 5", do(r, "PUT", "/users/5").Body.String())
+	assert.Equal(t, "PATCH 5", do(r, "PATCH", "/users/5").Body.String())
+	w := do(r, "DELETE", "/users/5")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, PUT, PATCH", w.Header().Get("Allow"))
+	assert.Equal(t, http.StatusMethodNotAllowed, do(r, "POST", "/users").Code)
+
+	routes := r.Routes()
+	assert.Equal(t, "*route.userController.Index", routes[0].HandlerName)
+}
+
+func TestControllerErrors(t *testing.T) {
+	r := &Router{}
+	assert.NotNil(t, r.ControllerE("/x", struct{}{}))
+	assert.NotNil(t, r.ControllerE("/x", badController{}))
+	r.Route("/users/:id").Method("GET").FuncE(F1)
+	assert.NotNil(t, r.ControllerE("/users", &userController{}))
+	// Nothing was registered by the failed call.
+	assert.Nil(t, r.lookupPath("/users", nil))
+}
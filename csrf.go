@@ -0,0 +1,126 @@
+package route
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"html/template"
+	"mime"
+	"net/http"
+)
+
+// CSRF is middleware protecting form-handling subtrees from cross-site
+// request forgery, with the double-submit cookie pattern:
+//
+//     r.Route("/account").Use(route.CSRF{Secure: true}.Middleware())
+//
+// Each client gets a random token in a cookie.  Requests with unsafe
+// methods, like POST, must echo the token in a header or form field;
+// a cross-site attacker can make a browser send the cookie, but can't
+// read it to echo it.  Other requests get a 403 Forbidden.  GET, HEAD,
+// OPTIONS and TRACE requests are exempt, so they must not change
+// state.
+//
+// Handlers put the token in forms with CSRFToken, or in templates with
+// the "csrfField" and "csrfToken" functions of FuncMap:
+//
+//     <form method="post">{{ csrfField .Request }} ... </form>
+type CSRF struct {
+	// CookieName, FieldName and HeaderName name the cookie holding
+	// the token, and the form field and header echoing it; "csrf",
+	// "csrf_token" and "X-CSRF-Token" by default.
+	CookieName string
+	FieldName  string
+	HeaderName string
+
+	// Path and Secure are attributes of the cookie, which is also
+	// HttpOnly and SameSite=Lax.  Path is "/" by default.
+	Path   string
+	Secure bool
+}
+
+// csrfKey is the context key of a request's CSRF settings and token.
+type csrfKey struct{}
+
+// csrfToken is the token of a request protected by CSRF.
+type csrfToken struct {
+	token, field string
+}
+
+// Middleware returns middleware applying c.
+func (c CSRF) Middleware() Middleware {
+	if c.CookieName == "" {
+		c.CookieName = "csrf"
+	}
+	if c.FieldName == "" {
+		c.FieldName = "csrf_token"
+	}
+	if c.HeaderName == "" {
+		c.HeaderName = "X-CSRF-Token"
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			token := ""
+			if cookie, err := req.Cookie(c.CookieName); err == nil && len(cookie.Value) == 43 {
+				token = cookie.Value
+			}
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			default:
+				if token == "" || !c.echoed(req, token) {
+					http.Error(w, "invalid CSRF token", http.StatusForbidden)
+					return
+				}
+			}
+			if token == "" {
+				b := make([]byte, 32)
+				rand.Read(b)
+				token = base64.RawURLEncoding.EncodeToString(b)
+				http.SetCookie(w, &http.Cookie{
+					Name:     c.CookieName,
+					Value:    token,
+					Path:     c.Path,
+					Secure:   c.Secure,
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			w.Header().Add("Vary", "Cookie")
+			req = req.WithContext(context.WithValue(req.Context(), csrfKey{}, csrfToken{token, c.FieldName}))
+			next(w, req, env)
+		}
+	}
+}
+
+// echoed reports whether req echoes token in the header or form field.
+func (c *CSRF) echoed(req *http.Request, token string) bool {
+	got := req.Header.Get(c.HeaderName)
+	if got == "" {
+		mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if mt == "application/x-www-form-urlencoded" || mt == "multipart/form-data" {
+			got = req.PostFormValue(c.FieldName)
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// CSRFToken returns the token a request protected by the CSRF
+// middleware must echo, for pages and scripts making further requests,
+// or "" if req is not protected.
+func CSRFToken(req *http.Request) string {
+	t, _ := req.Context().Value(csrfKey{}).(csrfToken)
+	return t.token
+}
+
+// csrfField returns a hidden form input holding req's CSRF token.
+func csrfField(req *http.Request) template.HTML {
+	t, _ := req.Context().Value(csrfKey{}).(csrfToken)
+	if t.token == "" {
+		return ""
+	}
+	return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(t.field) + `" value="` + t.token + `">`)
+}
@@ -0,0 +1,54 @@
+package route
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRF(t *testing.T) {
+	r := &Router{}
+	tmpl := template.Must(template.New("form").Funcs(r.FuncMap()).Parse(`<form>{{ csrfField . }}</form>`))
+	form := r.Route("/form")
+	form.Use(CSRF{}.Middleware())
+	form.Method("GET").Func(func(w http.ResponseWriter, req *http.Request) {
+		tmpl.Execute(w, req)
+	})
+	form.Method("POST").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("posted"))
+	})
+
+	page := get(r, "/form")
+	cookies := page.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+	token := cookies[0].Value
+	assert.Equal(t, `<form><input type="hidden" name="csrf_token" value="`+token+`"></form>`, page.Body.String())
+
+	submit := func(body, header string, withCookie bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/form", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if header != "" {
+			req.Header.Set("X-CSRF-Token", header)
+		}
+		if withCookie {
+			req.AddCookie(cookies[0])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, "posted", submit(url.Values{"csrf_token": {token}}.Encode(), "", true).Body.String())
+	assert.Equal(t, "posted", submit("", token, true).Body.String())
+	assert.Equal(t, http.StatusForbidden, submit("", "", true).Code)
+	assert.Equal(t, http.StatusForbidden, submit("csrf_token=forged", "", true).Code)
+	assert.Equal(t, http.StatusForbidden, submit(url.Values{"csrf_token": {token}}.Encode(), "", false).Code)
+
+	// Unprotected requests have no token.
+	assert.Equal(t, "", CSRFToken(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, template.HTML(""), csrfField(httptest.NewRequest("GET", "/", nil)))
+}
@@ -0,0 +1,56 @@
+package route
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var debugTemplate = template.Must(template.New("routes").Parse(`<!doctype html>
+<title>Routes</title>
+<style>
+body { font-family: sans-serif; }
+td, th { text-align: left; padding: 0.2em 1em 0.2em 0; }
+td { font-family: monospace; }
+</style>
+<table>
+<tr><th>Pattern</th><th>Methods</th><th>Name</th><th>Handler</th><th>Hits</th><th>Last hit</th></tr>
+{{range .}}<tr><td>{{.Pattern}}</td><td>{{range .Methods}}{{.}} {{else}}*{{end}}</td><td>{{.Name}}</td><td>{{.HandlerName}}</td><td>{{.Hits}}</td><td>{{if not .LastHit.IsZero}}{{.LastHit.Format "2006-01-02 15:04:05"}}{{end}}</td></tr>
+{{end}}</table>
+`))
+
+// DebugHandler returns a handler that renders the routing table beneath
+// r, for inspecting a running server.  It responds with JSON (in the
+// format of MarshalJSON) if the request's Accept header asks for
+// application/json or the query has format=json, and HTML otherwise.
+//
+// The table includes the traffic seen by each route, as reported by
+// Stats, while TrackStats is set.  It may reveal internal structure,
+// so mount it behind authentication, e.g. at "/_routes".
+func (r *Router) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("format") == "json" ||
+			strings.Contains(req.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			r.DumpJSON(w)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		stats := map[string]RouteStats{}
+		for _, s := range r.Stats() {
+			stats[s.Pattern] = s
+		}
+		type row struct {
+			RouteInfo
+			Hits    int64
+			LastHit time.Time
+		}
+		var rows []row
+		for _, info := range r.Routes() {
+			s := stats[info.Pattern]
+			rows = append(rows, row{info, s.Hits, s.LastHit})
+		}
+		debugTemplate.Execute(w, rows)
+	})
+}
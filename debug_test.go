@@ -0,0 +1,29 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugHandler(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Name("user.show").FuncE(F1)
+	h := r.DebugHandler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/_routes", nil))
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.Contains(w.Body.String(), "<td>/users/:id</td>"))
+	assert.True(t, strings.Contains(w.Body.String(), "<td>user.show</td>"))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/_routes?format=json", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var tree map[string]interface{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &tree))
+	assert.NotNil(t, tree["children"])
+}
@@ -0,0 +1,43 @@
+package route
+
+import "net/http"
+
+// SetDebugLog sets a function to log the matching decisions made for
+// each request served by r's tree, as reported by Explain, along with
+// the variant chosen for the request, if any.  Passing nil turns
+// logging off again.
+//
+// It is safe to call while serving, so logging can be toggled in
+// production, for example from an admin endpoint:
+//
+//     r.SetDebugLog(log.Printf)
+//     defer r.SetDebugLog(nil)
+//
+// Matching is slower while logging is on.
+func (r *Router) SetDebugLog(logf func(format string, args ...interface{})) {
+	root := r.root()
+	if logf == nil {
+		root.debugLog.Store(nil)
+	} else {
+		root.debugLog.Store(&logf)
+	}
+}
+
+// debugLookup is lookup for ServeHTTP, logging as it goes.
+func (r *Router) debugLookup(req *http.Request, path string, p *params, logf func(format string, args ...interface{})) HandlerE {
+	logf("route: %s %s", req.Method, path)
+	h := r.trace(path, 1, p, func(format string, args ...interface{}) {
+		logf("route:   "+format, args...)
+	})
+	switch {
+	case h == nil:
+		logf("route:   no route matches %q", path)
+	case len(p.node.variants) > 0:
+		if _, n, status := p.node.selectHandler(req); n != nil {
+			logf("route:   selected %s %v: %s", n.displayPattern(), n.conds, n.handlerName)
+		} else {
+			logf("route:   no variant matched; status %d", status)
+		}
+	}
+	return h
+}
@@ -0,0 +1,41 @@
+package route
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDebugLog(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Method("GET").FuncE(writeEnv("show "))
+	r.Route("/about").FuncE(F1)
+
+	var lines []string
+	r.Route("/users").SetDebugLog(func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+
+	assert.Equal(t, "show 5", get(r, "/users/5").Body.String())
+	assert.Equal(t, []string{
+		`route: GET /users/5`,
+		`route:   /: static "users" matched`,
+		`route:   /users: trying :id = "5"`,
+		`route:   /users/:id: matched; variants chosen per request: Method: GET`,
+		`route:   selected /users/:id [Method: GET]: github.com/evmar/route.writeEnv.func1`,
+	}, lines)
+
+	lines = nil
+	do(r, "POST", "/users/5")
+	assert.Equal(t, `route:   no variant matched; status 405`, lines[len(lines)-1])
+
+	lines = nil
+	get(r, "/nope")
+	assert.Equal(t, `route:   no route matches "/nope"`, lines[len(lines)-1])
+
+	r.SetDebugLog(nil)
+	lines = nil
+	get(r, "/about")
+	assert.Nil(t, lines)
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// DumpDot writes the matching tree beneath r to w as a Graphviz DOT
+// graph, for visualizing large routing tables.
+//
+// Each node is labeled with its pattern; nodes with handlers are drawn
+// as boxes and also show the handler name.  Edges are labeled with
+// the path component they match: a static name, ":var", or "*"; or,
+// for variants of a node (see Header), with the variant's condition.
+func (r *Router) DumpDot(w io.Writer) error {
+	defer r.rlock()()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph routes {")
+	id := 0
+	var visit func(n *Router) int
+	visit = func(n *Router) int {
+		me := id
+		id++
+		label := n.pattern
+		if label == "" {
+			label = "(root)"
+		}
+		if n.handler != nil {
+			fmt.Fprintf(bw, "  n%d [shape=box, label=%q];\n", me, label+"\n"+n.handlerName)
+		} else {
+			fmt.Fprintf(bw, "  n%d [label=%q];\n", me, label)
+		}
+		for _, v := range n.variants {
+			c := visit(v)
+			fmt.Fprintf(bw, "  n%d -> n%d [label=%q, style=bold];\n", me, c, "["+v.conds[len(v.conds)-1]+"]")
+		}
+		for _, k := range n.staticKeys() {
+			c := visit(n.matchers[k])
+			fmt.Fprintf(bw, "  n%d -> n%d [label=%q];\n", me, c, k)
+		}
+		if n.varRouter != nil {
+			c := visit(n.varRouter)
+			fmt.Fprintf(bw, "  n%d -> n%d [label=%q, style=dashed];\n", me, c, ":"+n.varName)
+		}
+		if n.fallbackRouter != nil {
+			c := visit(n.fallbackRouter)
+			fmt.Fprintf(bw, "  n%d -> n%d [label=\"*\", style=dotted];\n", me, c)
+		}
+		return me
+	}
+	visit(r)
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpDot(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+
+	var buf bytes.Buffer
+	assert.Nil(t, r.DumpDot(&buf))
+	assert.Equal(t, `digraph routes {
+  n0 [label="(root)"];
+  n1 [label="/static"];
+  n2 [shape=box, label="/static/*\ngithub.com/evmar/route.F1"];
+  n1 -> n2 [label="*", style=dotted];
+  n0 -> n1 [label="static"];
+  n3 [label="/users"];
+  n4 [shape=box, label="/users/:id\ngithub.com/evmar/route.F1"];
+  n3 -> n4 [label=":id", style=dashed];
+  n0 -> n3 [label="users"];
+}
+`, buf.String())
+}
@@ -0,0 +1,161 @@
+package route
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Env is the map of captures passed to a HandlerE, with accessors
+// parsing values into other types.  Convert a handler's env to use
+// them:
+//
+//     id, err := route.Env(env).Int("id")
+//
+// Each accessor returns an HTTPError with status 400 Bad Request if
+// the capture is missing or malformed, so FuncErr handlers can return
+// it as is.  The Must variants instead panic with that error, which
+// the router recovers and hands to the route's error handler (see
+// ErrorHandler), so a handler can simply write:
+//
+//     u := db.User(route.Env(env).MustInt("id"))
+type Env map[string]string
+
+// lookup returns the capture for key, or an error if there is none.
+func (e Env) lookup(key string) (string, error) {
+	v, ok := e[key]
+	if !ok {
+		return "", Errorf(http.StatusBadRequest, "missing %s", key)
+	}
+	return v, nil
+}
+
+// badValue returns the error for a malformed capture.
+func badValue(key, val string) error {
+	return Errorf(http.StatusBadRequest, "invalid %s %q", key, val)
+}
+
+// Int returns the capture for key as an int.
+func (e Env) Int(key string) (int, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, badValue(key, v)
+	}
+	return n, nil
+}
+
+// Int64 returns the capture for key as an int64.
+func (e Env) Int64(key string) (int64, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, badValue(key, v)
+	}
+	return n, nil
+}
+
+// UUID returns the capture for key, a UUID in the usual form
+// "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", as 16 bytes.  The result
+// converts directly to the UUID types of packages like
+// github.com/google/uuid.
+func (e Env) UUID(key string) ([16]byte, error) {
+	var u [16]byte
+	v, err := e.lookup(key)
+	if err != nil {
+		return u, err
+	}
+	if len(v) != 36 || v[8] != '-' || v[13] != '-' || v[18] != '-' || v[23] != '-' {
+		return u, badValue(key, v)
+	}
+	s := v[0:8] + v[9:13] + v[14:18] + v[19:23] + v[24:]
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, badValue(key, v)
+	}
+	return u, nil
+}
+
+// Bool returns the capture for key as a bool, accepting the values
+// accepted by strconv.ParseBool.
+func (e Env) Bool(key string) (bool, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badValue(key, v)
+	}
+	return b, nil
+}
+
+// Time returns the capture for key as a time, parsed with layout as
+// by time.Parse.
+func (e Env) Time(key, layout string) (time.Time, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(layout, v)
+	if err != nil {
+		return time.Time{}, badValue(key, v)
+	}
+	return t, nil
+}
+
+// MustInt is like Int, but panics with the error; see Env.
+func (e Env) MustInt(key string) int {
+	return must(e.Int(key))
+}
+
+// MustInt64 is like Int64, but panics with the error; see Env.
+func (e Env) MustInt64(key string) int64 {
+	return must(e.Int64(key))
+}
+
+// MustUUID is like UUID, but panics with the error; see Env.
+func (e Env) MustUUID(key string) [16]byte {
+	return must(e.UUID(key))
+}
+
+// MustBool is like Bool, but panics with the error; see Env.
+func (e Env) MustBool(key string) bool {
+	return must(e.Bool(key))
+}
+
+// MustTime is like Time, but panics with the error; see Env.
+func (e Env) MustTime(key, layout string) time.Time {
+	return must(e.Time(key, layout))
+}
+
+// badEnv is the panic value of Env's Must methods.
+type badEnv struct{ err error }
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(badEnv{err})
+	}
+	return v
+}
+
+// call runs h, handing the error from a panicking Must method of Env
+// to the error handler for n.
+func call(h HandlerE, n *Router, w http.ResponseWriter, req *http.Request, env map[string]string) {
+	defer func() {
+		if v := recover(); v != nil {
+			b, ok := v.(badEnv)
+			if !ok {
+				panic(v)
+			}
+			n.handleError(w, req, b.err)
+		}
+	}()
+	h(w, req, env)
+}
@@ -0,0 +1,66 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnv(t *testing.T) {
+	env := Env{"id": "42", "big": "9000000000", "ok": "true", "day": "2024-03-01",
+		"uuid": "123e4567-E89B-12d3-a456-426614174000", "bad": "x"}
+
+	n, err := env.Int("id")
+	assert.Nil(t, err)
+	assert.Equal(t, 42, n)
+	n64, err := env.Int64("big")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9000000000), n64)
+	b, err := env.Bool("ok")
+	assert.Nil(t, err)
+	assert.True(t, b)
+	d, err := env.Time("day", "2006-01-02")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), d)
+	u, err := env.UUID("uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, u)
+
+	_, err = env.Int("bad")
+	assert.Equal(t, `invalid bad "x"`, err.Error())
+	assert.Equal(t, http.StatusBadRequest, defaultMapper.Status(err))
+	_, err = env.UUID("id")
+	assert.NotNil(t, err)
+	_, err = env.Bool("missing")
+	assert.Equal(t, "missing missing", err.Error())
+	assert.Equal(t, http.StatusBadRequest, defaultMapper.Status(err))
+}
+
+func TestEnvMust(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		Env(env).MustInt("id")
+		w.Write([]byte("ok"))
+	})
+	r.Route("/api/:id").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		Env(env).MustUUID("id")
+	})
+	r.Route("/api").ProblemJSON(nil)
+
+	assert.Equal(t, "ok", get(r, "/users/1").Body.String())
+	w := get(r, "/users/x")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid id \"x\"\n", w.Body.String())
+
+	w = get(r, "/api/x")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	// Other panics are not recovered.
+	r.Route("/panic").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		panic("boom")
+	})
+	assert.Panics(t, func() { get(r, "/panic") })
+}
@@ -0,0 +1,157 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// FuncErr registers a handler that returns an error at the current
+// point.  Returning a non-nil error hands it to the error handler for
+// the route (see ErrorHandler), so one place decides status codes,
+// logging and response bodies:
+//
+//     r.Route("/users/:id").FuncErr(func(w http.ResponseWriter, r *http.Request, env map[string]string) error {
+//         u, err := db.User(env["id"])
+//         if err != nil {
+//             return err
+//         }
+//         return json.NewEncoder(w).Encode(u)
+//     })
+//
+// Like FuncE, it panics if a handler is already registered.
+func (r *Router) FuncErr(f func(w http.ResponseWriter, r *http.Request, env map[string]string) error) {
+	defer r.lock()()
+	bind := func(n *Router) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if err := f(w, req, env); err != nil {
+				n.handleError(w, req, err)
+			}
+		}
+	}
+	if err := r.setBoundHandler(bind, funcName(f)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// ErrorHandler sets the function that handles errors returned by
+// handlers registered with FuncErr at or beneath the current point.
+// As with NotFound, a handler set on a subtree overrides one set
+// nearer the root.
+//
+// Without one, errors are handled by a zero ErrorMapper: an HTTPError
+// gets its status, and anything else is logged and gets a 500 Internal
+// Server Error, without the error's text.
+func (r *Router) ErrorHandler(f func(w http.ResponseWriter, r *http.Request, err error)) {
+	defer r.lock()()
+	r.mustNotBeFrozen()
+	r.errorHandler = f
+}
+
+// handleError passes err, returned by the handler at r, to the error
+// handler inherited by r.
+func (r *Router) handleError(w http.ResponseWriter, req *http.Request, err error) {
+	unlock := r.rlock()
+	f := defaultMapper.Handle
+	for n := r; n != nil; n = n.parent {
+		if n.errorHandler != nil {
+			f = n.errorHandler
+			break
+		}
+	}
+	unlock()
+	f(w, req, err)
+}
+
+// defaultMapper handles errors for routes with no ErrorHandler.
+var defaultMapper ErrorMapper
+
+// HTTPError is an error carrying the HTTP status it should be reported
+// with; see Errorf.  Err may be nil, in which case the error's message
+// is the status text.
+type HTTPError struct {
+	Status int
+	Err    error
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Status)
+	}
+	return e.Err.Error()
+}
+
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// Errorf returns an HTTPError with the given status and a message
+// formatted as by fmt.Errorf, so %w wraps an underlying error:
+//
+//     return route.Errorf(http.StatusNotFound, "no user %q", env["id"])
+func Errorf(status int, format string, args ...interface{}) error {
+	return &HTTPError{Status: status, Err: fmt.Errorf(format, args...)}
+}
+
+// ErrorMapper maps errors returned by handlers to HTTP statuses, so
+// sentinel errors from other packages can be translated centrally:
+//
+//     var errs route.ErrorMapper
+//     errs.Map(sql.ErrNoRows, http.StatusNotFound)
+//     errs.Map(context.DeadlineExceeded, http.StatusGatewayTimeout)
+//     r.ErrorHandler(errs.Handle)
+//
+// The zero ErrorMapper maps only HTTPErrors.  Map must not be called
+// concurrently with Status or Handle.
+type ErrorMapper struct {
+	entries []mapping
+}
+
+type mapping struct {
+	err    error
+	status int
+}
+
+// Map reports errors matching target, as by errors.Is, with status.
+// Mappings are tried in the order they were added.
+func (m *ErrorMapper) Map(target error, status int) {
+	m.entries = append(m.entries, mapping{target, status})
+}
+
+// Status returns the HTTP status for err: the status of the outermost
+// HTTPError in its chain, if any, otherwise the first mapping matching
+// it, otherwise 413 Request Entity Too Large for a
+// *http.MaxBytesError (see MaxBodySize), otherwise 500 Internal Server
+// Error.
+func (m *ErrorMapper) Status(err error) int {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he.Status
+	}
+	for _, e := range m.entries {
+		if errors.Is(err, e.err) {
+			return e.status
+		}
+	}
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// Handle responds to a request whose handler failed with err, for use
+// with Router.ErrorHandler.  The response has the status from Status.
+// The text of HTTPErrors with 4xx statuses is sent to the client; for
+// other errors, which may contain internal details, only the status
+// text is sent, and 5xx errors are logged.
+func (m *ErrorMapper) Handle(w http.ResponseWriter, req *http.Request, err error) {
+	status := m.Status(err)
+	msg := http.StatusText(status)
+	var he *HTTPError
+	if status < 500 && errors.As(err, &he) {
+		msg = he.Error()
+	}
+	if status >= 500 {
+		logf(req, "%s", err)
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,106 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func failWith(err error) func(http.ResponseWriter, *http.Request, map[string]string) error {
+	return func(w http.ResponseWriter, r *http.Request, env map[string]string) error {
+		if err == nil {
+			w.Write([]byte("ok"))
+		}
+		return err
+	}
+}
+
+func TestFuncErr(t *testing.T) {
+	r := &Router{}
+	r.Route("/ok").FuncErr(failWith(nil))
+	r.Route("/fail").FuncErr(failWith(errors.New("secret")))
+	r.Route("/api/fail").FuncErr(failWith(errors.New("api")))
+	r.Route("/api").ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte(err.Error()))
+	})
+
+	assert.Equal(t, "ok", get(r, "/ok").Body.String())
+
+	w := get(r, "/fail")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	w = get(r, "/api/fail")
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "api", w.Body.String())
+
+	assert.Panics(t, func() { r.Route("/ok").FuncErr(failWith(nil)) })
+}
+
+func TestErrorMapper(t *testing.T) {
+	errMissing := errors.New("missing")
+	var m ErrorMapper
+	m.Map(errMissing, http.StatusNotFound)
+
+	assert.Equal(t, http.StatusNotFound, m.Status(errMissing))
+	assert.Equal(t, http.StatusNotFound, m.Status(fmt.Errorf("user 5: %w", errMissing)))
+	assert.Equal(t, http.StatusConflict, m.Status(Errorf(http.StatusConflict, "taken: %w", errMissing)))
+	assert.Equal(t, http.StatusInternalServerError, m.Status(errors.New("other")))
+
+	r := &Router{}
+	r.ErrorHandler(m.Handle)
+	r.Route("/a").FuncErr(failWith(fmt.Errorf("db: %w", errMissing)))
+	r.Route("/b").FuncErr(failWith(Errorf(http.StatusBadRequest, "bad id %q", "x")))
+	r.Route("/c").FuncErr(failWith(Errorf(http.StatusBadGateway, "upstream secret")))
+
+	w := get(r, "/a")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "Not Found\n", w.Body.String())
+	w = get(r, "/b")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "bad id \"x\"\n", w.Body.String())
+	w = get(r, "/c")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, "Bad Gateway\n", w.Body.String())
+}
+
+func TestDefaultErrorHandler(t *testing.T) {
+	r := &Router{}
+	r.Route("/a").FuncErr(failWith(Errorf(http.StatusForbidden, "no")))
+	w := get(r, "/a")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "no\n", w.Body.String())
+
+	r.Route("/b").FuncErr(failWith(&HTTPError{Status: http.StatusConflict}))
+	w = get(r, "/b")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "Conflict\n", w.Body.String())
+}
+
+func TestFuncErrMerged(t *testing.T) {
+	teapot := func(w http.ResponseWriter, req *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+	api := &Router{}
+	api.Route("/fail").FuncErr(failWith(errors.New("boom")))
+	api.Route("/stream").Stream(func(s *Stream, env map[string]string) error {
+		return errors.New("boom")
+	})
+
+	r := &Router{}
+	r.ErrorHandler(teapot)
+	assert.Nil(t, r.Merge(api, "/api"))
+	assert.Equal(t, http.StatusTeapot, get(r, "/api/fail").Code)
+	assert.Equal(t, http.StatusTeapot, get(r, "/api/stream").Code)
+
+	r2 := &Router{}
+	r2.ErrorHandler(teapot)
+	other := &Router{}
+	other.FuncErr(failWith(errors.New("boom")))
+	assert.Nil(t, r2.Route("/v1").Swap(other))
+	assert.Equal(t, http.StatusTeapot, get(r2, "/v1").Code)
+}
@@ -0,0 +1,149 @@
+package route
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag is middleware adding entity tags to responses and answering
+// conditional requests with 304 Not Modified, for read-heavy subtrees:
+//
+//     r.Route("/api/catalog").Use(route.ETag{}.Middleware())
+//
+// Successful responses to GET and HEAD requests are buffered, and
+// unless the handler set an ETag header itself, get one computed from
+// a hash of the body.  Requests with a matching If-None-Match header,
+// or, in its absence, an If-Modified-Since header no earlier than the
+// response's Last-Modified header, get a 304 without the body.
+type ETag struct {
+	// Weak marks computed tags as weak (W/"..."), for responses that
+	// are semantically but not byte-for-byte equivalent.
+	Weak bool
+}
+
+// Middleware returns middleware applying e.
+func (e ETag) Middleware() Middleware {
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				next(w, req, env)
+				return
+			}
+			buf := newResponseBuffer()
+			next(buf, req, env)
+			h := buf.Header()
+			if buf.code == http.StatusOK {
+				if h.Get("ETag") == "" {
+					sum := sha256.Sum256(buf.body.Bytes())
+					tag := hashTag(sum[:])
+					if e.Weak {
+						tag = "W/" + tag
+					}
+					h.Set("ETag", tag)
+				}
+				if notModified(req, h) {
+					for _, k := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+						h.Del(k)
+					}
+					buf.code = http.StatusNotModified
+					buf.body.Reset()
+				}
+			}
+			buf.writeTo(w)
+		}
+	}
+}
+
+// hashTag returns a strong entity tag for content with the given
+// SHA-256 hash.
+func hashTag(sum []byte) string {
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified reports whether the conditional headers of req are
+// satisfied by a response with header h, so that it can be answered
+// with a 304.
+func notModified(req *http.Request, h http.Header) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, h.Get("ETag"))
+	}
+	ims, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lm, err := http.ParseTime(h.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !lm.Truncate(time.Second).After(ims)
+}
+
+// etagMatch reports whether the If-None-Match list matches etag, using
+// the weak comparison.
+func etagMatch(list, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(list, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseBuffer is an http.ResponseWriter that records a response,
+// for middleware that needs to see it whole.
+type responseBuffer struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{header: make(http.Header)}
+}
+
+func (b *responseBuffer) Header() http.Header { return b.header }
+
+func (b *responseBuffer) WriteHeader(code int) {
+	if b.code == 0 {
+		b.code = code
+	}
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	if b.code == 0 {
+		b.code = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// writeTo sends the recorded response to w.
+func (b *responseBuffer) writeTo(w http.ResponseWriter) {
+	copyHeader(w.Header(), b.header)
+	if b.code == 0 {
+		b.code = http.StatusOK
+	}
+	w.WriteHeader(b.code)
+	w.Write(b.body.Bytes())
+}
+
+// copyHeader copies the fields of src into dst, replacing those there,
+// except that Vary is added to: it lists the request headers read by
+// every layer of handlers, outer ones included.
+func copyHeader(dst, src http.Header) {
+	for k, v := range src {
+		if k == "Vary" {
+			dst[k] = append(dst[k], v...)
+		} else {
+			dst[k] = v
+		}
+	}
+}
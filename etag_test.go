@@ -0,0 +1,63 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func conditional(h http.Handler, method, path string, headers ...string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestETag(t *testing.T) {
+	r := &Router{}
+	r.Use(ETag{}.Middleware())
+	r.Route("/catalog").Func(writeString("items"))
+	r.Route("/dated").Func(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Thu, 01 Jan 2026 00:00:00 GMT")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("dated"))
+	})
+	r.Route("/missing").Func(http.NotFound)
+
+	w := get(r, "/catalog")
+	tag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[\w-]+"$`, tag)
+	assert.Equal(t, "items", w.Body.String())
+	assert.Equal(t, tag, get(r, "/catalog").Header().Get("ETag"))
+
+	w = conditional(r, "GET", "/catalog", "If-None-Match", `"other", `+tag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, tag, w.Header().Get("ETag"))
+	assert.Equal(t, "", w.Header().Get("Content-Type"))
+	assert.Equal(t, 0, w.Body.Len())
+
+	assert.Equal(t, http.StatusOK, conditional(r, "GET", "/catalog", "If-None-Match", `"other"`).Code)
+	assert.Equal(t, http.StatusNotModified, conditional(r, "GET", "/dated", "If-None-Match", `W/"v1"`).Code)
+	assert.Equal(t, http.StatusNotModified, conditional(r, "GET", "/dated", "If-Modified-Since", "Thu, 01 Jan 2026 00:00:00 GMT").Code)
+	assert.Equal(t, http.StatusOK, conditional(r, "GET", "/dated", "If-Modified-Since", "Wed, 31 Dec 2025 00:00:00 GMT").Code)
+	// If-None-Match takes precedence.
+	assert.Equal(t, http.StatusOK, conditional(r, "GET", "/dated",
+		"If-None-Match", `"v0"`, "If-Modified-Since", "Thu, 01 Jan 2026 00:00:00 GMT").Code)
+
+	assert.Equal(t, "", conditional(r, "POST", "/catalog").Header().Get("ETag"))
+	w = get(r, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "", w.Header().Get("ETag"))
+}
+
+func TestETagWeak(t *testing.T) {
+	r := &Router{}
+	r.Use(ETag{Weak: true}.Middleware())
+	r.Route("/").Func(writeString("x"))
+	assert.Regexp(t, `^W/"`, get(r, "/").Header().Get("ETag"))
+}
@@ -0,0 +1,106 @@
+package route
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain returns a step-by-step trace of how r matches path: each
+// static child looked up, variable tried, fallback taken and
+// backtrack, ending with the node matched, if any.  It is meant for
+// working out why a URL 404s in a large tree:
+//
+//     for _, step := range r.Explain("/users/5/edti") {
+//         fmt.Println(step)
+//     }
+//
+// with routes for "/users/new" and "/users/:id" prints
+//
+//     /: static "users" matched
+//     /users: no static child "5"
+//     /users: trying :id = "5"
+//     /users/:id: no match for "edti"
+//     /users: backtracking from /users/:id
+//     /users: no match for "5"
+//     /: backtracking from /users
+//     /: no match for "users"
+//     no route matches "/users/5/edti"
+func (r *Router) Explain(path string) []string {
+	var steps []string
+	logf := func(format string, args ...interface{}) {
+		steps = append(steps, fmt.Sprintf(format, args...))
+	}
+	if path == "" || path[0] != '/' {
+		logf("%q does not start with /", path)
+		return steps
+	}
+	defer r.rlock()()
+	var p params
+	if r.trace(path, 1, &p, logf) == nil {
+		logf("no route matches %q", path)
+	}
+	return steps
+}
+
+// trace is lookup, reporting each decision it makes to logf.  It
+// ignores the path compression done by Compile, which doesn't affect
+// the result.
+func (r *Router) trace(path string, i int, p *params, logf func(format string, args ...interface{})) HandlerE {
+	at := r.displayPattern()
+	if i > len(path) {
+		h := r.nodeHandler()
+		switch {
+		case h == nil:
+			logf("%s: end of path, but no handler", at)
+			return nil
+		case len(r.variants) > 0:
+			var conds []string
+			for _, v := range r.variants {
+				conds = append(conds, v.conds[len(v.conds)-1])
+			}
+			logf("%s: matched; variants chosen per request: %s", at, strings.Join(conds, "; "))
+		default:
+			logf("%s: matched %s", at, r.handlerName)
+		}
+		p.node = r
+		return r.wrap(h)
+	}
+
+	comp, next := segment(path, i)
+	if r.matchers != nil {
+		key := comp
+		if r.CaseInsensitive {
+			key = strings.ToLower(comp)
+		}
+		if r2 := r.matchers[key]; r2 != nil {
+			logf("%s: static %q matched", at, comp)
+			if h := r2.trace(path, next, p, logf); h != nil {
+				if key != comp {
+					p.folded = true
+				}
+				return r.wrap(h)
+			}
+			logf("%s: backtracking from %s", at, r2.displayPattern())
+		} else {
+			logf("%s: no static child %q", at, comp)
+		}
+	}
+	if comp != "" && r.varRouter != nil {
+		logf("%s: trying :%s = %q", at, r.varName, comp)
+		n := p.n
+		p.add(r.varName, comp)
+		if h := r.varRouter.trace(path, next, p, logf); h != nil {
+			return r.wrap(h)
+		}
+		logf("%s: backtracking from %s", at, r.varRouter.displayPattern())
+		p.n = n
+	}
+	if r.fallbackRouter != nil {
+		logf("%s: fallback * = %q", at, path[i:])
+		p.add("*", path[i:])
+		p.node = r.fallbackRouter
+		return r.wrap(r.fallbackRouter.wrap(r.fallbackRouter.nodeHandler()))
+	}
+	logf("%s: no match for %q", at, comp)
+	return nil
+}
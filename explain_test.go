@@ -0,0 +1,51 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/users/new").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+
+	assert.Equal(t, []string{
+		`/: static "users" matched`,
+		`/users: no static child "5"`,
+		`/users: trying :id = "5"`,
+		`/users/:id: no match for "edit"`,
+		`/users: backtracking from /users/:id`,
+		`/users: no match for "5"`,
+		`/: backtracking from /users`,
+		`/: no match for "users"`,
+		`no route matches "/users/5/edit"`,
+	}, r.Explain("/users/5/edit"))
+
+	assert.Equal(t, []string{
+		`/: static "users" matched`,
+		`/users: static "new" matched`,
+		`/users/new: matched github.com/evmar/route.F1`,
+	}, r.Explain("/users/new"))
+
+	assert.Equal(t, []string{
+		`/: static "static" matched`,
+		`/static: fallback * = "a/b.css"`,
+	}, r.Explain("/static/a/b.css"))
+
+	assert.Equal(t, []string{`"x" does not start with /`}, r.Explain("x"))
+}
+
+func TestExplainVariants(t *testing.T) {
+	r := &Router{}
+	a := r.Route("/a/b")
+	a.Method("GET").FuncE(F1)
+	a.Header("X-Debug", "").FuncE(F1)
+	assert.Equal(t, []string{
+		`/: static "a" matched`,
+		`/a: static "b" matched`,
+		`/a/b: matched; variants chosen per request: Method: GET; X-Debug`,
+	}, r.Explain("/a/b"))
+}
@@ -0,0 +1,100 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// DefaultJSONBodySize is the size limit FuncJSON applies to request
+// bodies for routes without one set by MaxBodySize.
+const DefaultJSONBodySize = 1 << 20
+
+// FuncJSON registers a handler at r for a JSON API endpoint, taking
+// care of the plumbing around f:
+//
+//     type createUser struct{ Name string }
+//
+//     route.FuncJSON(r.Route("/users").Method("POST"), func(ctx context.Context, in createUser, env route.Env) (*User, error) {
+//         return db.CreateUser(ctx, in.Name)
+//     })
+//
+// The request body is decoded into a Req, rejecting unknown fields,
+// trailing data and Content-Types other than JSON, and limited to the
+// route's MaxBodySize or else DefaultJSONBodySize.  An empty body
+// leaves Req as its zero value, as suits GET requests.  If Req has a
+// Validate() error method, it is called next.  f's result is then
+// encoded as the JSON response, with status 200 OK.
+//
+// Errors from any step are reported through the route's error handler
+// (see ErrorHandler), as for FuncErr: a malformed body or a failed
+// Validate, unless it returns an HTTPError itself, gets 400 Bad
+// Request, and errors from f are passed on as is.
+//
+// Like FuncE, it panics if a handler is already registered.
+func FuncJSON[Req, Resp any](r *Router, f func(ctx context.Context, req Req, env Env) (Resp, error)) {
+	defer r.lock()()
+	bind := func(n *Router) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			in, err := decodeJSON[Req](n, w, req)
+			if err == nil {
+				var out Resp
+				if out, err = f(req.Context(), in, env); err == nil {
+					w.Header().Set("Content-Type", "application/json")
+					err = json.NewEncoder(w).Encode(out)
+				}
+			}
+			if err != nil {
+				n.handleError(w, req, err)
+			}
+		}
+	}
+	if err := r.setBoundHandler(bind, funcName(f)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// decodeJSON decodes and validates the body of a request to n.
+func decodeJSON[T any](n *Router, w http.ResponseWriter, req *http.Request) (T, error) {
+	var v T
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		if mt, _, _ := mime.ParseMediaType(ct); mt != "application/json" {
+			return v, Errorf(http.StatusUnsupportedMediaType, "unsupported content type %q", ct)
+		}
+	}
+	unlock := n.rlock()
+	limit := n.bodyLimit()
+	unlock()
+	if limit == 0 && req.Body != nil && req.Body != http.NoBody {
+		req.Body = http.MaxBytesReader(w, req.Body, DefaultJSONBodySize)
+	}
+	if req.Body != nil {
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&v)
+		if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+			err = errors.New("trailing data after JSON value")
+		}
+		var mbe *http.MaxBytesError
+		switch {
+		case err == io.EOF:
+		case errors.As(err, &mbe):
+			return v, err
+		case err != nil:
+			return v, Errorf(http.StatusBadRequest, "bad request body: %w", err)
+		}
+	}
+	if val, ok := interface{}(&v).(interface{ Validate() error }); ok {
+		if err := val.Validate(); err != nil {
+			var he *HTTPError
+			if !errors.As(err, &he) {
+				err = &HTTPError{Status: http.StatusBadRequest, Err: err}
+			}
+			return v, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type greetReq struct {
+	Name string `json:"name"`
+}
+
+func (g greetReq) Validate() error {
+	if g.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type greetResp struct {
+	Greeting string `json:"greeting"`
+}
+
+func postType(h http.Handler, path, contentType, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestFuncJSON(t *testing.T) {
+	r := &Router{}
+	FuncJSON(r.Route("/greet/:lang"), func(ctx context.Context, in greetReq, env Env) (greetResp, error) {
+		if env["lang"] == "fr" {
+			return greetResp{"bonjour " + in.Name}, nil
+		}
+		return greetResp{}, Errorf(http.StatusNotFound, "no language %s", env["lang"])
+	})
+
+	w := postType(r, "/greet/fr", "application/json; charset=utf-8", `{"name": "ann"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"greeting":"bonjour ann"}`+"\n", w.Body.String())
+
+	w = post(r, "/greet/de", `{"name": "ann"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	for _, body := range []string{`{"name": "ann", "age": 3}`, `{"name": "ann"} {}`, `{"name":`, `{}`, ``} {
+		w = post(r, "/greet/fr", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, "name is required\n", post(r, "/greet/fr", `{}`).Body.String())
+
+	w = postType(r, "/greet/fr", "text/plain", `{"name": "ann"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = post(r, "/greet/fr", `{"name": "`+strings.Repeat("a", DefaultJSONBodySize)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	r.Route("/greet").MaxBodySize(10)
+	w = post(r, "/greet/fr", `{"name": "ann"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
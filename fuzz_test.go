@@ -0,0 +1,62 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func FuzzLookup(f *testing.F) {
+	for _, seed := range []string{
+		"", "x", "/", "//", "/users/5", "/users/5/", "/USERS/new",
+		"/static/a/../b", "/ünï/çødé", "/\xff\xfe", "/a\x00b",
+		strings.Repeat("/a", 10000),
+	} {
+		f.Add(seed)
+	}
+	r := &Router{CaseInsensitive: true, RedirectTrailingSlash: true}
+	r.Route("/").FuncE(F1)
+	r.Route("/users/new").FuncE(F1)
+	r.Route("/users/:id").Method("GET").FuncE(F1)
+	r.Route("/users/:id/posts/:post").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+	r.Route("/a/a/a").FuncE(F1)
+
+	f.Fuzz(func(t *testing.T, path string) {
+		env := map[string]string{}
+		h := r.lookupPath(path, env)
+		if (path == "" || path[0] != '/') && h != nil {
+			t.Errorf("%q: matched a path not starting with /", path)
+		}
+		r.Match("GET", path)
+		r.Explain(path)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, &http.Request{Method: "GET", URL: &url.URL{Path: path}, Header: http.Header{}})
+	})
+}
+
+func FuzzRoute(f *testing.F) {
+	for _, seed := range []string{
+		"", "/", "//", "/users/:id", "/:", "/users/:id/:id", "/*", "/*/x",
+		"/a//b", "/a/", "/ünï/:çødé", strings.Repeat("/:v", 100),
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, pattern string) {
+		r := &Router{Strict: true}
+		n, err := r.RouteE(pattern)
+		if err != nil {
+			return
+		}
+		if err := n.TryFuncE(F1); err != nil {
+			t.Fatalf("%q: %s", pattern, err)
+		}
+		r.Routes()
+		r.DumpString()
+		r.Check()
+		r.Compile()
+		r.Match("GET", pattern)
+	})
+}
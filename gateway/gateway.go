@@ -0,0 +1,234 @@
+// Package gateway registers routes described by google.api.http
+// annotations, as used by grpc-gateway, so that services mixing gRPC
+// and REST can serve both from one route.Router.
+//
+// Annotations give each RPC a method and a path template, in which
+// "{field}" binds a path component to a request field:
+//
+//     rpc GetBook(GetBookRequest) returns (Book) {
+//         option (google.api.http) = { get: "/v1/shelves/{shelf}/books/{book}" };
+//     }
+//
+// The template is converted to a route pattern, here
+// "/v1/shelves/:shelf/books/:book", and the values captured for each
+// field are passed to the handler in its env, keyed by field path.
+// This is the pathParams argument of grpc-gateway's generated
+// handlers, so they can be registered directly:
+//
+//     gateway.Register(r, "GET", "/v1/shelves/{shelf}/books/{book}", getBookHandler)
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evmar/route"
+)
+
+// Rule is a google.api.http annotation, with the fields of the
+// HttpRule message, or of an "http" rule in a service configuration
+// file.  Exactly one of Get, Put, Post, Delete, Patch and Custom
+// should be set.
+type Rule struct {
+	// Selector is the full name of the RPC the rule applies to, like
+	// "library.v1.Library.GetBook".
+	Selector string
+
+	Get, Put, Post, Delete, Patch string
+	// Custom is for other methods, like HEAD.
+	Custom struct{ Kind, Path string }
+
+	// AdditionalBindings are further routes to the same RPC.
+	AdditionalBindings []Rule
+}
+
+// binding returns the method and path template of the rule.
+func (rule *Rule) binding() (method, template string, err error) {
+	for _, b := range []struct{ method, template string }{
+		{http.MethodGet, rule.Get},
+		{http.MethodPut, rule.Put},
+		{http.MethodPost, rule.Post},
+		{http.MethodDelete, rule.Delete},
+		{http.MethodPatch, rule.Patch},
+		{rule.Custom.Kind, rule.Custom.Path},
+	} {
+		if b.template == "" {
+			continue
+		}
+		if template != "" {
+			return "", "", fmt.Errorf("gateway: %s: more than one pattern", rule.Selector)
+		}
+		method, template = b.method, b.template
+	}
+	if template == "" {
+		return "", "", fmt.Errorf("gateway: %s: no pattern", rule.Selector)
+	}
+	return method, template, nil
+}
+
+// RegisterRules registers the routes of each rule, and its additional
+// bindings, on r, with the handler that handlers maps its Selector to.
+func RegisterRules(r *route.Router, rules []Rule, handlers map[string]route.HandlerE) error {
+	for i := range rules {
+		rule := &rules[i]
+		h := handlers[rule.Selector]
+		if h == nil {
+			return fmt.Errorf("gateway: no handler for %s", rule.Selector)
+		}
+		method, template, err := rule.binding()
+		if err != nil {
+			return err
+		}
+		if err := Register(r, method, template, h); err != nil {
+			return err
+		}
+		for _, extra := range rule.AdditionalBindings {
+			extra.Selector = rule.Selector
+			if err := RegisterRules(r, []Rule{extra}, handlers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Register registers h on r for requests with the given method and a
+// path matching the google.api.http path template.  See Pattern for
+// the templates supported.
+func Register(r *route.Router, method, template string, h route.HandlerE) error {
+	pattern, vars, err := parse(template)
+	if err != nil {
+		return err
+	}
+	n, err := r.RouteE(pattern)
+	if err != nil {
+		return fmt.Errorf("gateway: %s: %w", template, err)
+	}
+	if len(vars) > 0 {
+		h = bind(h, vars)
+	}
+	if err := n.Method(method).TryFuncE(h); err != nil {
+		return fmt.Errorf("gateway: %s %s: %w", method, template, err)
+	}
+	return nil
+}
+
+// A variable is a field bound to several path components, like
+// "{name=shelves/*}", which the route captures in parts: literal
+// components as is, and wildcards in the env keys named by vars.
+type variable struct {
+	field string
+	parts []string
+	vars  []bool
+}
+
+// bind wraps h to assemble vars from the values captured for them.
+func bind(h route.HandlerE, vars []variable) route.HandlerE {
+	return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		for _, v := range vars {
+			vals := make([]string, len(v.parts))
+			for i, part := range v.parts {
+				vals[i] = part
+				if v.vars[i] {
+					vals[i] = env[part]
+					delete(env, part)
+				}
+			}
+			env[v.field] = strings.Join(vals, "/")
+		}
+		h(w, req, env)
+	}
+}
+
+// Pattern converts a google.api.http path template to a route
+// pattern.  Variables bound to a single component, "{id}" or
+// "{id=*}", become route variables; those bound to several, like
+// "{name=shelves/*}" or "{path=**}", have their wildcards captured
+// under names given by position, like ":#2", that Register reassembles
+// them from.  Such a wildcard conflicts with a named variable in the
+// same place in another template.  A "**" may only be the last
+// component, and a verb, like ":cancel", may only follow a literal
+// component, with which it is matched.
+func Pattern(template string) (string, error) {
+	pattern, _, err := parse(template)
+	return pattern, err
+}
+
+// parse is Pattern, also returning the variables bound to several
+// components.
+func parse(template string) (pattern string, vars []variable, err error) {
+	if !strings.HasPrefix(template, "/") {
+		return "", nil, fmt.Errorf("gateway: %s: must start with /", template)
+	}
+	var out []string
+	rest := template[1:]
+	for rest != "" {
+		var seg string
+		if rest[0] == '{' {
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return "", nil, fmt.Errorf("gateway: %s: unterminated variable", template)
+			}
+			seg, rest = rest[:end+1], rest[end+1:]
+		} else if i := strings.IndexByte(rest, '/'); i >= 0 {
+			seg, rest = rest[:i], rest[i:]
+		} else {
+			seg, rest = rest, ""
+		}
+		if rest != "" {
+			if rest[0] != '/' {
+				return "", nil, fmt.Errorf("gateway: %s: verb after a variable is unsupported", template)
+			}
+			rest = rest[1:]
+			if rest == "" {
+				out = append(out, seg, "")
+				break
+			}
+		}
+		last := rest == ""
+
+		switch {
+		case seg == "*":
+			out = append(out, ":#"+strconv.Itoa(len(out)))
+		case seg == "**":
+			if !last {
+				return "", nil, fmt.Errorf("gateway: %s: ** must be last", template)
+			}
+			out = append(out, "*")
+		case strings.HasPrefix(seg, "{"):
+			field, sub, _ := strings.Cut(seg[1:len(seg)-1], "=")
+			if field == "" {
+				return "", nil, fmt.Errorf("gateway: %s: empty variable name", template)
+			}
+			if sub == "" || sub == "*" {
+				out = append(out, ":"+field)
+				continue
+			}
+			v := variable{field: field}
+			subs := strings.Split(sub, "/")
+			for i, s := range subs {
+				switch {
+				case s == "*":
+					name := "#" + strconv.Itoa(len(out))
+					out = append(out, ":"+name)
+					v.parts, v.vars = append(v.parts, name), append(v.vars, true)
+				case s == "**":
+					if !last || i != len(subs)-1 {
+						return "", nil, fmt.Errorf("gateway: %s: ** must be last", template)
+					}
+					out = append(out, "*")
+					v.parts, v.vars = append(v.parts, "*"), append(v.vars, true)
+				default:
+					out = append(out, s)
+					v.parts, v.vars = append(v.parts, s), append(v.vars, false)
+				}
+			}
+			vars = append(vars, v)
+		default:
+			out = append(out, seg)
+		}
+	}
+	return "/" + strings.Join(out, "/"), vars, nil
+}
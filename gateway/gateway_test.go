@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+)
+
+// echo writes the pathParams it receives.
+func echo(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	var kv []string
+	for k, v := range pathParams {
+		kv = append(kv, k+"="+v)
+	}
+	sort.Strings(kv)
+	fmt.Fprint(w, strings.Join(kv, " "))
+}
+
+func TestPattern(t *testing.T) {
+	for _, test := range []struct{ template, pattern string }{
+		{"/v1/books/{id}", "/v1/books/:id"},
+		{"/v1/{user.id=*}/posts", "/v1/:user.id/posts"},
+		{"/v1/{name=shelves/*/books/*}", "/v1/shelves/:#2/books/:#4"},
+		{"/v1/files/{path=**}", "/v1/files/*"},
+		{"/v1/*/info", "/v1/:#1/info"},
+		{"/v1/books:batchGet", "/v1/books:batchGet"},
+	} {
+		pattern, err := Pattern(test.template)
+		assert.Nil(t, err, test.template)
+		assert.Equal(t, test.pattern, pattern, test.template)
+	}
+	for _, bad := range []string{"v1/books", "/v1/{id}:cancel", "/v1/**/x", "/v1/{id", "/v1/{=*}"} {
+		_, err := Pattern(bad)
+		assert.NotNil(t, err, bad)
+	}
+}
+
+func TestRegisterRules(t *testing.T) {
+	r := &route.Router{}
+	rules := []Rule{
+		{Selector: "library.GetBook", Get: "/v1/{name=shelves/*/books/*}",
+			AdditionalBindings: []Rule{{Get: "/v1/books/{name}"}}},
+		{Selector: "library.CreateBook", Post: "/v1/{parent=shelves/*}/books"},
+		{Selector: "files.Get", Custom: struct{ Kind, Path string }{"HEAD", "/v1/files/{path=**}"}},
+	}
+	handlers := map[string]route.HandlerE{"library.GetBook": echo, "library.CreateBook": echo, "files.Get": echo}
+	assert.Nil(t, RegisterRules(r, rules, handlers))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	assert.Equal(t, "name=shelves/s1/books/b2", serve("GET", "/v1/shelves/s1/books/b2").Body.String())
+	assert.Equal(t, "name=b2", serve("GET", "/v1/books/b2").Body.String())
+	assert.Equal(t, "parent=shelves/s1", serve("POST", "/v1/shelves/s1/books").Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("DELETE", "/v1/shelves/s1/books").Code)
+	assert.Equal(t, http.StatusOK, serve("HEAD", "/v1/files/a/b.txt").Code)
+
+	assert.NotNil(t, RegisterRules(r, []Rule{{Selector: "x.Missing", Get: "/x"}}, handlers))
+	assert.NotNil(t, RegisterRules(r, []Rule{{Selector: "files.Get", Get: "/x", Post: "/x"}}, handlers))
+	assert.NotNil(t, Register(r, "PUT", "/v1/shelves/{shelf}/books", echo))
+}
@@ -0,0 +1,66 @@
+package route
+
+import "net/http"
+
+// GraphQLOptions configures GraphQL.
+type GraphQLOptions struct {
+	// Playground, if set, serves GET requests from browsers, those
+	// accepting text/html, typically with an in-browser IDE like
+	// GraphiQL.  Leave it nil in production to keep the schema
+	// explorer private.
+	Playground http.Handler
+
+	// GETQueries passes other GET requests, carrying the query in the
+	// URL, to the handler.  The handler must take care to run only
+	// queries, not mutations, for them.
+	GETQueries bool
+}
+
+// GraphQL registers h, a GraphQL server such as one generated from a
+// schema, at the current point, so that a GraphQL API slots in next
+// to REST routes:
+//
+//     r.Route("/graphql").GraphQL(srv, route.GraphQLOptions{Playground: graphiql})
+//
+// POST requests carry operations, and must have a Content-Type of
+// application/json or application/graphql, or get a 415 Unsupported
+// Media Type.  GET requests are served according to the options; with
+// neither option set they get a 405 Method Not Allowed, as do other
+// methods.  With only Playground set, GET requests that don't accept
+// text/html get a 406 Not Acceptable.  At most one GraphQLOptions may
+// be given.
+//
+// GraphQL panics if handlers are already registered at the current
+// point.
+func (r *Router) GraphQL(h http.Handler, opts ...GraphQLOptions) {
+	if len(opts) > 1 {
+		panic("route: GraphQL takes at most one GraphQLOptions")
+	}
+	var o GraphQLOptions
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+	post := r.Method(http.MethodPost)
+	post.ContentType("application/json").handle(h)
+	post.ContentType("application/graphql").handle(h)
+	if o.Playground != nil || o.GETQueries {
+		get := r.Method(http.MethodGet)
+		if o.Playground != nil {
+			get.Accept("text/html").handle(o.Playground)
+		}
+		if o.GETQueries {
+			get.handle(h)
+		}
+	}
+}
+
+// handle registers h at the current point, panicking if a handler is
+// already registered.
+func (r *Router) handle(h http.Handler) {
+	defer r.lock()()
+	if err := r.setHandler(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		h.ServeHTTP(w, req)
+	}, handlerName(h)); err != nil {
+		panic(err.Error())
+	}
+}
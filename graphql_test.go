@@ -0,0 +1,44 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	r := &Router{}
+	server := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("graphql " + r.Method))
+	})
+	playground := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("playground"))
+	})
+	r.Route("/graphql").GraphQL(server, GraphQLOptions{Playground: playground})
+	r.Route("/api/graphql").GraphQL(server, GraphQLOptions{GETQueries: true})
+	r.Route("/private/graphql").GraphQL(server)
+
+	serve := func(method, path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"query":"{ me { id } }"}`))
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "graphql POST", serve("POST", "/graphql", "Content-Type", "application/json; charset=utf-8").Body.String())
+	assert.Equal(t, "graphql POST", serve("POST", "/graphql", "Content-Type", "application/graphql").Body.String())
+	assert.Equal(t, http.StatusUnsupportedMediaType, serve("POST", "/graphql", "Content-Type", "text/plain").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("PUT", "/graphql", "Content-Type", "application/json").Code)
+
+	assert.Equal(t, "playground", serve("GET", "/graphql", "Accept", "text/html").Body.String())
+	assert.Equal(t, http.StatusNotAcceptable, serve("GET", "/graphql", "Accept", "application/json").Code)
+
+	assert.Equal(t, "graphql GET", serve("GET", "/api/graphql?query={me{id}}").Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("GET", "/private/graphql").Code)
+}
@@ -0,0 +1,61 @@
+package route
+
+import "net/http"
+
+// Middleware wraps a handler with additional behavior, such as
+// logging or authentication.  See Use.
+type Middleware func(next HandlerE) HandlerE
+
+// Use adds middleware to the current point in the tree.  It wraps the
+// handler of every route at or beneath this point, including routes
+// registered after the call.  Middleware added to nodes nearer the
+// root runs first; among middleware added to one node, the first
+// added runs first.
+//
+// Use returns the router, to allow chaining.
+func (r *Router) Use(mw ...Middleware) *Router {
+	defer r.lock()()
+	r.invalidate()
+	r.middleware = append(r.middleware, mw...)
+	return r
+}
+
+// UseHTTP adds standard func(http.Handler) http.Handler middleware, as
+// used by many third-party packages, to the current point.  See Use.
+func (r *Router) UseHTTP(mw func(http.Handler) http.Handler) *Router {
+	return r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				next(w, req, env)
+			})).ServeHTTP(w, req)
+		}
+	})
+}
+
+// wrap applies r's middleware to h.  It returns nil if h is nil.
+func (r *Router) wrap(h HandlerE) HandlerE {
+	if h == nil {
+		return nil
+	}
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+	return h
+}
+
+// Group calls fn with the router for prefix, so that a block of
+// related routes, and the middleware they share, read as a unit:
+//
+//     r.Group("/api/v1", func(g *route.Router) {
+//         g.Use(requireAuth)
+//         g.Route("/users/:id").FuncE(showUser)
+//         g.Route("/users/:id/edit").FuncE(editUser)
+//     })
+//
+// Any middleware added to g applies to every route beneath the prefix.
+// Group returns the prefix's router.
+func (r *Router) Group(prefix string, fn func(g *Router)) *Router {
+	g := r.Route(prefix)
+	fn(g)
+	return g
+}
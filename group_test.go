@@ -0,0 +1,63 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tag(s string) Middleware {
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+			w.Write([]byte(s))
+			next(w, r, env)
+		}
+	}
+}
+
+func writeOK(w http.ResponseWriter, r *http.Request, env map[string]string) {
+	w.Write([]byte("ok"))
+}
+
+func TestUse(t *testing.T) {
+	r := &Router{}
+	r.Use(tag("root "))
+	r.Route("/a").Use(tag("a1 "), tag("a2 "))
+	r.Route("/a/b").FuncE(writeOK)
+	r.Route("/a/:id").Use(tag("var ")).FuncE(writeOK)
+	r.Route("/files/*").Use(tag("files ")).FuncE(writeOK)
+
+	assert.Equal(t, "root a1 a2 ok", get(r, "/a/b").Body.String())
+	assert.Equal(t, "root a1 a2 var ok", get(r, "/a/x").Body.String())
+	assert.Equal(t, "root files ok", get(r, "/files/x/y").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/a").Code)
+}
+
+func TestUseHTTP(t *testing.T) {
+	r := &Router{}
+	r.UseHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "1")
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Route("/:id").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		w.Write([]byte(env["id"]))
+	})
+	w := get(r, "/5")
+	assert.Equal(t, "1", w.Header().Get("X-Test"))
+	assert.Equal(t, "5", w.Body.String())
+}
+
+func TestGroup(t *testing.T) {
+	r := &Router{}
+	g := r.Group("/api/v1", func(g *Router) {
+		g.Use(tag("api "))
+		g.Route("/users").FuncE(writeOK)
+	})
+	r.Route("/").FuncE(writeOK)
+	assert.Equal(t, "/api/v1", g.pattern)
+	assert.Equal(t, "api ok", get(r, "/api/v1/users").Body.String())
+	assert.Equal(t, "ok", get(r, "/").Body.String())
+}
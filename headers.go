@@ -0,0 +1,24 @@
+package route
+
+import "net/http"
+
+// SetHeader sets a response header for routes at and beneath the
+// current point, and returns the router to allow chaining:
+//
+//     r.Route("/api").SetHeader("Cache-Control", "no-store").SetHeader("API-Version", "2")
+//
+// The header is set before the handler runs, so handlers can override
+// it, as can SetHeader on a subtree; an empty value there removes it.
+func (r *Router) SetHeader(key, value string) *Router {
+	key = http.CanonicalHeaderKey(key)
+	return r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if value == "" {
+				w.Header().Del(key)
+			} else {
+				w.Header().Set(key, value)
+			}
+			next(w, req, env)
+		}
+	})
+}
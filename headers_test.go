@@ -0,0 +1,28 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetHeader(t *testing.T) {
+	r := &Router{}
+	api := r.Route("/api").SetHeader("cache-control", "no-store").SetHeader("API-Version", "2")
+	api.Route("/users").FuncE(F1)
+	api.Route("/cached").SetHeader("Cache-Control", "max-age=60").FuncE(F1)
+	api.Route("/plain").SetHeader("API-Version", "").FuncE(F1)
+	api.Route("/custom").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Cache-Control", "private")
+	})
+	r.Route("/other").FuncE(F1)
+
+	h := get(r, "/api/users").Header()
+	assert.Equal(t, "no-store", h.Get("Cache-Control"))
+	assert.Equal(t, "2", h.Get("API-Version"))
+	assert.Equal(t, "max-age=60", get(r, "/api/cached").Header().Get("Cache-Control"))
+	assert.Equal(t, []string(nil), get(r, "/api/plain").Header().Values("API-Version"))
+	assert.Equal(t, "private", get(r, "/api/custom").Header().Get("Cache-Control"))
+	assert.Equal(t, "", get(r, "/other").Header().Get("Cache-Control"))
+}
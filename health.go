@@ -0,0 +1,100 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// HealthCheck is a named check of a dependency for Readiness, like a
+// database ping.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// healthReport is the JSON body served by Liveness and Readiness.
+type healthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness registers a liveness probe at path beneath the current
+// point, answering GET requests with 200 OK and {"status":"ok"} for as
+// long as the server can serve requests at all:
+//
+//     r.Liveness("/healthz")
+func (r *Router) Liveness(path string) {
+	r.Route(path).Method(http.MethodGet).FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		writeHealth(w, &healthReport{Status: "ok"})
+	})
+}
+
+// Readiness registers a readiness probe at path beneath the current
+// point, running checks concurrently for each GET request and
+// reporting their results as JSON:
+//
+//     r.Readiness("/readyz",
+//         route.HealthCheck{Name: "db", Check: db.PingContext},
+//         route.HealthCheck{Name: "cache", Check: pingCache})
+//
+// answers, with 200 OK if every check passes and 503 Service
+// Unavailable otherwise,
+//
+//     {"status":"fail","checks":{"cache":"ok","db":"dial tcp: connection refused"}}
+//
+// Checks run with the request's context, so the prober's timeout, or
+// Timeout middleware, bounds them.  A check that panics fails.
+// Readiness panics if two checks have the same name.
+func (r *Router) Readiness(path string, checks ...HealthCheck) {
+	names := map[string]bool{}
+	for _, c := range checks {
+		if names[c.Name] {
+			panic("route: duplicate health check " + c.Name)
+		}
+		names[c.Name] = true
+	}
+	r.Route(path).Method(http.MethodGet).FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		report := &healthReport{Status: "ok", Checks: make(map[string]string, len(checks))}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, c := range checks {
+			wg.Add(1)
+			go func(c HealthCheck) {
+				defer wg.Done()
+				err := runCheck(req.Context(), c)
+				mu.Lock()
+				defer mu.Unlock()
+				report.Checks[c.Name] = "ok"
+				if err != nil {
+					report.Checks[c.Name] = err.Error()
+					report.Status = "fail"
+				}
+			}(c)
+		}
+		wg.Wait()
+		writeHealth(w, report)
+	})
+}
+
+// runCheck runs c, turning a panic into an error.
+func runCheck(ctx context.Context, c HealthCheck) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return c.Check(ctx)
+}
+
+// writeHealth writes report, with a status reflecting it.
+func writeHealth(w http.ResponseWriter, report *healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
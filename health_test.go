@@ -0,0 +1,50 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	r := &Router{}
+	r.Liveness("/healthz")
+	var dbErr error
+	r.Readiness("/readyz",
+		HealthCheck{Name: "db", Check: func(ctx context.Context) error { return dbErr }},
+		HealthCheck{Name: "cache", Check: func(ctx context.Context) error { return nil }})
+
+	w := get(r, "/healthz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"status":"ok"}`+"\n", w.Body.String())
+
+	w = get(r, "/readyz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"status":"ok","checks":{"cache":"ok","db":"ok"}}`+"\n", w.Body.String())
+
+	dbErr = errors.New("connection refused")
+	w = get(r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, `{"status":"fail","checks":{"cache":"ok","db":"connection refused"}}`+"\n", w.Body.String())
+
+	// An error reading "ok" is still a failure.
+	dbErr = errors.New("ok")
+	assert.Equal(t, http.StatusServiceUnavailable, get(r, "/readyz").Code)
+}
+
+func TestHealthPanic(t *testing.T) {
+	r := &Router{}
+	r.Readiness("/readyz", HealthCheck{Name: "db", Check: func(ctx context.Context) error { panic("boom") }})
+	w := get(r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, `{"status":"fail","checks":{"db":"panic: boom"}}`+"\n", w.Body.String())
+
+	noop := func(ctx context.Context) error { return nil }
+	assert.Panics(t, func() {
+		r.Readiness("/ready2", HealthCheck{Name: "db", Check: noop}, HealthCheck{Name: "db", Check: noop})
+	})
+}
@@ -0,0 +1,69 @@
+package route
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// HostRouter selects a Router by the request's Host before matching
+// paths, so one server can serve several virtual hosts with separate
+// route trees:
+//
+//     h := &route.HostRouter{}
+//     h.Host("api.example.com").Route("/users/:id").FuncE(showUser)
+//     h.Host("www.example.com").Route("/").Func(home)
+//     http.ListenAndServe(":8080", h)
+//
+// Host names are compared case-insensitively, ignoring any port.
+type HostRouter struct {
+	mu    sync.RWMutex
+	hosts map[string]*Router
+}
+
+// Host returns the router for the given host name, creating it if
+// necessary.  The empty host name is the default, used for requests
+// whose host matches no other entry.  A name beginning with "*." like
+// "*.example.com" matches any subdomain of example.com.
+func (h *HostRouter) Host(host string) *Router {
+	host = strings.ToLower(host)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.hosts == nil {
+		h.hosts = make(map[string]*Router)
+	}
+	if h.hosts[host] == nil {
+		h.hosts[host] = &Router{}
+	}
+	return h.hosts[host]
+}
+
+// router returns the router for a request's host, or nil.
+func (h *HostRouter) router(host string) *Router {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if r := h.hosts[host]; r != nil {
+		return r
+	}
+	for i := strings.IndexByte(host, '.'); i >= 0; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		if r := h.hosts["*."+host]; r != nil {
+			return r
+		}
+	}
+	return h.hosts[""]
+}
+
+// ServeHTTP dispatches the request to the router for its host.
+func (h *HostRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r := h.router(req.Host); r != nil {
+		r.ServeHTTP(w, req)
+		return
+	}
+	http.NotFound(w, req)
+}
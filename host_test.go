@@ -0,0 +1,37 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeString(s string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(s))
+	}
+}
+
+func TestHostRouter(t *testing.T) {
+	h := &HostRouter{}
+	h.Host("api.example.com").Route("/").Func(writeString("api"))
+	h.Host("*.example.com").Route("/").Func(writeString("sub"))
+
+	getHost := func(host string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Host = host
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "api", getHost("api.example.com").Body.String())
+	assert.Equal(t, "api", getHost("API.example.com:8080").Body.String())
+	assert.Equal(t, "sub", getHost("a.b.example.com").Body.String())
+	assert.Equal(t, http.StatusNotFound, getHost("other.com").Code)
+
+	h.Host("").Route("/").Func(writeString("default"))
+	assert.Equal(t, "default", getHost("other.com").Body.String())
+}
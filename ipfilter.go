@@ -0,0 +1,52 @@
+package route
+
+import (
+	"net/http"
+	"net/netip"
+)
+
+// IPFilter is middleware restricting requests by client address, for
+// locking internal routes down at the router:
+//
+//     admin := r.Route("/admin")
+//     admin.Use(route.IPFilter{
+//         Allow:          []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+//         TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")},
+//     }.Middleware())
+//
+// Requests from addresses in Deny, or, if Allow is non-empty, not in
+// Allow, get a 403 Forbidden.
+type IPFilter struct {
+	Allow, Deny []netip.Prefix
+
+	// TrustedProxies lists the proxies whose forwarding headers are
+	// believed when determining the client address.  If nil, the
+	// address is determined by ClientIP, using the TrustedProxies of
+	// the Router.
+	TrustedProxies []netip.Prefix
+}
+
+// Allowed reports whether f lets requests from addr through.
+func (f IPFilter) Allowed(addr netip.Addr) bool {
+	if !addr.IsValid() || contains(f.Deny, addr) {
+		return false
+	}
+	return len(f.Allow) == 0 || contains(f.Allow, addr)
+}
+
+// Middleware returns middleware applying f.
+func (f IPFilter) Middleware() Middleware {
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			addr := ClientIP(req)
+			if f.TrustedProxies != nil {
+				addr = clientIP(req, f.TrustedProxies)
+			}
+			if !f.Allowed(addr) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next(w, req, env)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fromAddr(h http.Handler, path, remote, xff string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", path, nil)
+	req.RemoteAddr = remote
+	if xff != "" {
+		req.Header.Set("X-Forwarded-For", xff)
+	}
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestIPFilter(t *testing.T) {
+	r := &Router{}
+	r.Route("/public").FuncE(F1)
+	admin := r.Route("/admin")
+	admin.Use(IPFilter{
+		Allow:          []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")},
+		Deny:           []netip.Prefix{netip.MustParsePrefix("10.9.0.0/16")},
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("192.168.0.1/32")},
+	}.Middleware())
+	admin.FuncE(F1)
+
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/public", "1.2.3.4:5", ""))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/admin", "1.2.3.4:5", ""))
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/admin", "10.1.2.3:5", ""))
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/admin", "[::1]:5", ""))
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/admin", "[::ffff:10.1.2.3]:5", ""))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/admin", "10.9.2.3:5", ""))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/admin", "garbage", ""))
+
+	// X-Forwarded-For is only believed from a trusted proxy.
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/admin", "192.168.0.1:5", "1.2.3.4, 10.1.2.3"))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/admin", "192.168.0.1:5", "10.1.2.3, 1.2.3.4"))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/admin", "1.2.3.4:5", "10.1.2.3"))
+}
+
+func TestIPFilterRouterProxies(t *testing.T) {
+	r := &Router{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("192.168.0.1/32")}}
+	r.Use(IPFilter{Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}.Middleware())
+	r.Route("/").FuncE(F1)
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/", "192.168.0.1:5", "10.1.2.3"))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/", "192.168.0.1:5", "1.2.3.4"))
+}
+
+func TestIPFilterUnknownHop(t *testing.T) {
+	internal := netip.MustParsePrefix("10.0.0.0/8")
+	r := &Router{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")}}
+	r.Use(IPFilter{Allow: []netip.Prefix{internal}}.Middleware())
+	r.Route("/").FuncE(F1)
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/", "10.0.0.1:5", "unknown"))
+	assert.Equal(t, http.StatusForbidden, fromAddr(r, "/", "10.0.0.1:5", "10.1.2.3, "))
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/", "10.0.0.1:5", "10.1.2.3"))
+}
@@ -0,0 +1,77 @@
+package route
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonNode is the JSON representation of a single node of the tree.
+type jsonNode struct {
+	Pattern   string               `json:"pattern"`
+	Condition string               `json:"condition,omitempty"`
+	Name      string               `json:"name,omitempty"`
+	Handler   string               `json:"handler,omitempty"`
+	Methods   []string             `json:"methods,omitempty"`
+	Variants  []*jsonNode          `json:"variants,omitempty"`
+	Children  map[string]*jsonNode `json:"children,omitempty"`
+	VarName   string               `json:"varName,omitempty"`
+	Var       *jsonNode            `json:"var,omitempty"`
+	Fallback  *jsonNode            `json:"fallback,omitempty"`
+	Hits      int64                `json:"hits,omitempty"`
+	LastHit   string               `json:"lastHit,omitempty"`
+}
+
+func (r *Router) toJSON() *jsonNode {
+	n := &jsonNode{
+		Pattern: r.pattern,
+		Name:    r.name,
+		Handler: r.handlerName,
+		Methods: r.methods(),
+		Hits:    r.hits.Load(),
+	}
+	if s := r.stats(); !s.LastHit.IsZero() {
+		n.LastHit = s.LastHit.UTC().Format(time.RFC3339)
+	}
+	if len(r.conds) > 0 {
+		n.Condition = r.conds[len(r.conds)-1]
+	}
+	for _, v := range r.variants {
+		n.Variants = append(n.Variants, v.toJSON())
+	}
+	if len(r.matchers) > 0 {
+		n.Children = make(map[string]*jsonNode, len(r.matchers))
+		for k, m := range r.matchers {
+			n.Children[k] = m.toJSON()
+		}
+	}
+	if r.varRouter != nil {
+		n.VarName = r.varName
+		n.Var = r.varRouter.toJSON()
+	}
+	if r.fallbackRouter != nil {
+		n.Fallback = r.fallbackRouter.toJSON()
+	}
+	return n
+}
+
+// MarshalJSON encodes the routing tree beneath r as JSON.
+//
+// Each node is an object with its "pattern", and where present the
+// route "name", "handler" name, "methods", "variants" (each with its
+// "condition"; see Header), static "children" keyed by path component,
+// "var" child (with its "varName"), and "fallback" child, and the
+// number of "hits" and time of the "lastHit" recorded by TrackStats.
+func (r *Router) MarshalJSON() ([]byte, error) {
+	defer r.rlock()()
+	return json.Marshal(r.toJSON())
+}
+
+// DumpJSON writes the routing tree beneath r to w as indented JSON.
+// See MarshalJSON for the format.
+func (r *Router) DumpJSON(w io.Writer) error {
+	defer r.rlock()()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.toJSON())
+}
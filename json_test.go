@@ -0,0 +1,29 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Name("user.show").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+
+	data, err := json.Marshal(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"pattern":"","children":{`+
+		`"static":{"pattern":"/static","fallback":{"pattern":"/static/*","handler":"github.com/evmar/route.F1"}},`+
+		`"users":{"pattern":"/users","varName":"id","var":{"pattern":"/users/:id","name":"user.show","handler":"github.com/evmar/route.F1"}}}}`,
+		string(data))
+
+	var buf bytes.Buffer
+	assert.Nil(t, r.DumpJSON(&buf))
+	var a, b interface{}
+	assert.Nil(t, json.Unmarshal(data, &a))
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &b))
+	assert.Equal(t, a, b)
+}
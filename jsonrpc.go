@@ -0,0 +1,207 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Standard JSON-RPC 2.0 error codes.
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+)
+
+// RPCError is a JSON-RPC 2.0 error object.  A method registered with
+// JSONRPC can return one to control the error sent.
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string { return e.Message }
+
+type rpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	// ID is nil for notifications, which get no response.
+	ID json.RawMessage `json:"id"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// JSONRPC registers a JSON-RPC 2.0 endpoint for POST requests at the
+// current point, dispatching calls by name to the functions in
+// methods, so RPC-style services get the router's middleware and
+// introspection:
+//
+//     r.Route("/rpc").JSONRPC(map[string]interface{}{
+//         "users.get":  func(ctx context.Context, p GetUserParams) (*User, error) { ... },
+//         "server.now": func(ctx context.Context) (time.Time, error) { ... },
+//     })
+//
+// Each function takes the request's context and, optionally, a value
+// decoded from the call's params, and returns a result, encoded as
+// JSON, and an error.  Errors are sent as is if they are *RPCErrors;
+// others are logged and reported as internal errors.  Batches of calls
+// are supported, and notifications, calls without an id, get no
+// response.
+//
+// JSONRPC panics if a function has the wrong signature, or if a
+// handler is already registered; see JSONRPCE.
+func (r *Router) JSONRPC(methods map[string]interface{}) {
+	if err := r.JSONRPCE(methods); err != nil {
+		log.Panic(err)
+	}
+}
+
+// JSONRPCE is like JSONRPC, but returns an error rather than panicking.
+func (r *Router) JSONRPCE(methods map[string]interface{}) error {
+	funcs := make(map[string]reflect.Value, len(methods))
+	names := make([]string, 0, len(methods))
+	for name, f := range methods {
+		v := reflect.ValueOf(f)
+		t := v.Type()
+		if t.Kind() != reflect.Func || t.NumIn() < 1 || t.NumIn() > 2 || t.In(0) != contextType ||
+			t.NumOut() != 2 || t.Out(1) != errorType {
+			return fmt.Errorf("route: JSON-RPC method %q has signature %s, want func(context.Context[, P]) (R, error)", name, t)
+		}
+		funcs[name] = v
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		body = bytes.TrimSpace(body)
+		var out interface{}
+		if len(body) > 0 && body[0] == '[' {
+			var batch []json.RawMessage
+			if err := json.Unmarshal(body, &batch); err != nil {
+				out = rpcFailure(nil, RPCParseError, "parse error")
+			} else if len(batch) == 0 {
+				out = rpcFailure(nil, RPCInvalidRequest, "invalid request")
+			} else {
+				var resps []*rpcResponse
+				for _, call := range batch {
+					if resp := rpcCall(req.Context(), funcs, call); resp != nil {
+						resps = append(resps, resp)
+					}
+				}
+				if resps != nil {
+					out = resps
+				}
+			}
+		} else if resp := rpcCall(req.Context(), funcs, body); resp != nil {
+			out = resp
+		}
+		if out == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out)
+	}
+
+	post := r.Method(http.MethodPost)
+	defer post.lock()()
+	return post.setHandler(h, "route.JSONRPC("+strings.Join(names, ", ")+")")
+}
+
+// rpcCall performs the call encoded in data, returning its response,
+// or nil for a notification.
+func rpcCall(ctx context.Context, funcs map[string]reflect.Value, data json.RawMessage) *rpcResponse {
+	var call rpcRequest
+	if err := json.Unmarshal(data, &call); err != nil {
+		if _, ok := err.(*json.SyntaxError); ok {
+			return rpcFailure(nil, RPCParseError, "parse error")
+		}
+		return rpcFailure(nil, RPCInvalidRequest, "invalid request")
+	}
+	if call.Version != "2.0" || call.Method == "" {
+		return rpcFailure(call.ID, RPCInvalidRequest, "invalid request")
+	}
+	resp := rpcInvoke(ctx, funcs, &call)
+	if call.ID == nil {
+		return nil
+	}
+	return resp
+}
+
+// logRPC logs a failure of a call to method, with the ID of its
+// request, if any (see RequestIDs).
+func logRPC(ctx context.Context, method string, err error) {
+	if id := contextRequestID(ctx); id != "" {
+		method += " [" + id + "]"
+	}
+	log.Printf("route: JSON-RPC %s: %s", method, err)
+}
+
+// rpcInvoke calls the function for call.
+func rpcInvoke(ctx context.Context, funcs map[string]reflect.Value, call *rpcRequest) *rpcResponse {
+	f, ok := funcs[call.Method]
+	if !ok {
+		return rpcFailure(call.ID, RPCMethodNotFound, "method not found")
+	}
+	args := []reflect.Value{reflect.ValueOf(ctx)}
+	if f.Type().NumIn() == 2 {
+		p := reflect.New(f.Type().In(1))
+		if len(call.Params) > 0 {
+			if err := json.Unmarshal(call.Params, p.Interface()); err != nil {
+				return rpcFailure(call.ID, RPCInvalidParams, "invalid params: "+err.Error())
+			}
+		}
+		args = append(args, p.Elem())
+	}
+	outs := f.Call(args)
+	if err, _ := outs[1].Interface().(error); err != nil {
+		var re *RPCError
+		if errors.As(err, &re) {
+			return &rpcResponse{Version: "2.0", Error: re, ID: call.ID}
+		}
+		logRPC(ctx, call.Method, err)
+		return rpcFailure(call.ID, RPCInternalError, "internal error")
+	}
+	result, err := json.Marshal(outs[0].Interface())
+	if err != nil {
+		logRPC(ctx, call.Method, err)
+		return rpcFailure(call.ID, RPCInternalError, "internal error")
+	}
+	return &rpcResponse{Version: "2.0", Result: result, ID: call.ID}
+}
+
+// rpcFailure returns an error response.  A nil id, for calls whose id
+// couldn't be read, is sent as null.
+func rpcFailure(id json.RawMessage, code int, msg string) *rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{Version: "2.0", Error: &RPCError{Code: code, Message: msg}, ID: id}
+}
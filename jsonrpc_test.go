@@ -0,0 +1,63 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRPC(t *testing.T) {
+	type addParams struct{ A, B int }
+	r := &Router{}
+	r.Route("/rpc").JSONRPC(map[string]interface{}{
+		"add": func(ctx context.Context, p addParams) (int, error) { return p.A + p.B, nil },
+		"fail": func(ctx context.Context) (interface{}, error) {
+			return nil, &RPCError{Code: 1, Message: "nope"}
+		},
+		"crash": func(ctx context.Context) (interface{}, error) { return nil, errors.New("db down") },
+	})
+
+	call := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/rpc", strings.NewReader(body)))
+		return w
+	}
+
+	w := call(`{"jsonrpc":"2.0","method":"add","params":{"A":1,"B":2},"id":7}`)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":3,"id":7}`+"\n", w.Body.String())
+
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":1,"message":"nope"},"id":"x"}`+"\n",
+		call(`{"jsonrpc":"2.0","method":"fail","id":"x"}`).Body.String())
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"internal error"},"id":1}`+"\n",
+		call(`{"jsonrpc":"2.0","method":"crash","id":1}`).Body.String())
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"},"id":1}`+"\n",
+		call(`{"jsonrpc":"2.0","method":"nope","id":1}`).Body.String())
+	assert.Contains(t, call(`{"jsonrpc":"2.0","method":"add","params":[1],"id":1}`).Body.String(), `"code":-32602`)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error"},"id":null}`+"\n",
+		call(`{"jsonrpc`).Body.String())
+	assert.Contains(t, call(`{"method":"add","id":1}`).Body.String(), `"code":-32600`)
+	assert.Contains(t, call(`[]`).Body.String(), `"code":-32600`)
+
+	// Notifications get no response.
+	assert.Equal(t, http.StatusNoContent, call(`{"jsonrpc":"2.0","method":"add"}`).Code)
+
+	w = call(`[
+		{"jsonrpc":"2.0","method":"add","params":{"A":2,"B":2},"id":1},
+		{"jsonrpc":"2.0","method":"add"},
+		{"jsonrpc":"2.0","method":"nope","id":2}
+	]`)
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":4,"id":1},{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"},"id":2}]`+"\n", w.Body.String())
+	assert.Equal(t, http.StatusNoContent, call(`[{"jsonrpc":"2.0","method":"add"}]`).Code)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, get(r, "/rpc").Code)
+	assert.Contains(t, r.DumpString(), "route.JSONRPC(add, crash, fail)")
+
+	err := (&Router{}).JSONRPCE(map[string]interface{}{"bad": func(int) int { return 0 }})
+	assert.NotNil(t, err)
+}
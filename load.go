@@ -0,0 +1,127 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig is the format of the documents read by Load.
+type LoadConfig struct {
+	Routes []LoadRoute `json:"routes" yaml:"routes"`
+}
+
+// LoadRoute is a single route in a LoadConfig.
+type LoadRoute struct {
+	// Path is the route's pattern, as passed to Route.
+	Path string `json:"path" yaml:"path"`
+	// Methods, if any, restricts the route to these methods; see Method.
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// Handler is the name of the handler in the Registry.
+	Handler string `json:"handler" yaml:"handler"`
+	// Name, if set, names the route for reverse routing; see Name.
+	// With Methods, the name goes on the first method's variant, so
+	// that routes for other methods at the same path can be named too.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Middleware lists the names of middleware in the Registry to
+	// apply to the route, outermost first.  It wraps only this
+	// route's handler, not routes beneath its path.
+	Middleware []string `json:"middleware,omitempty" yaml:"middleware,omitempty"`
+}
+
+// Registry maps the names used in route configuration documents to
+// Go handlers and middleware.
+type Registry struct {
+	Handlers   map[string]HandlerE
+	Middleware map[string]Middleware
+}
+
+// Load registers the routes described by a YAML or JSON document on r,
+// so routing can be adjusted without recompiling.  A document looks
+// like:
+//
+//     routes:
+//       - path: /users/:id
+//         methods: [GET]
+//         handler: users.show
+//         name: user.show
+//       - path: /admin/*
+//         handler: admin
+//         middleware: [auth]
+//
+// Handler names are looked up in handlers.  Use LoadRegistry for
+// documents that refer to middleware.
+func Load(r *Router, cfg []byte, handlers map[string]HandlerE) error {
+	return LoadRegistry(r, cfg, &Registry{Handlers: handlers})
+}
+
+// LoadRegistry is like Load, but resolves both handler and middleware
+// names through reg.
+//
+// Paths are registered in Strict mode.  Either every route in the
+// document is registered or, if there is any error, none are.
+func LoadRegistry(r *Router, cfg []byte, reg *Registry) error {
+	var doc LoadConfig
+	if trimmed := bytes.TrimSpace(cfg); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		if err := json.Unmarshal(cfg, &doc); err != nil {
+			return fmt.Errorf("route: %w", err)
+		}
+	} else if err := yaml.Unmarshal(cfg, &doc); err != nil {
+		return fmt.Errorf("route: %w", err)
+	}
+
+	tmp := &Router{Strict: true, CaseInsensitive: r.CaseInsensitive}
+	for i, lr := range doc.Routes {
+		if err := tmp.load(lr, reg); err != nil {
+			return fmt.Errorf("route: route %d (%s): %w", i, lr.Path, err)
+		}
+	}
+	return r.Merge(tmp, "")
+}
+
+func (r *Router) load(lr LoadRoute, reg *Registry) error {
+	h := reg.Handlers[lr.Handler]
+	if h == nil {
+		return fmt.Errorf("unknown handler %q", lr.Handler)
+	}
+	wrapped := h
+	for i := len(lr.Middleware) - 1; i >= 0; i-- {
+		m := reg.Middleware[lr.Middleware[i]]
+		if m == nil {
+			return fmt.Errorf("unknown middleware %q", lr.Middleware[i])
+		}
+		wrapped = m(wrapped)
+	}
+	n, err := r.RouteE(lr.Path)
+	if err != nil {
+		return err
+	}
+	targets := []*Router{n}
+	if len(lr.Methods) > 0 {
+		targets = targets[:0]
+		for _, m := range lr.Methods {
+			targets = append(targets, n.Method(m))
+		}
+	}
+	if lr.Name != "" {
+		t := targets[0]
+		unlock := t.lock()
+		err := t.checkName(lr.Name)
+		unlock()
+		if err != nil {
+			return err
+		}
+		t.Name(lr.Name)
+	}
+	for _, t := range targets {
+		unlock := t.lock()
+		err := t.setHandler(wrapped, funcName(h))
+		unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
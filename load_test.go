@@ -0,0 +1,76 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeEnv(s string) HandlerE {
+	return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		w.Write([]byte(s + env["id"]))
+	}
+}
+
+var testRegistry = &Registry{
+	Handlers: map[string]HandlerE{
+		"users.show": writeEnv("show "),
+		"admin":      writeEnv("admin"),
+	},
+	Middleware: map[string]Middleware{
+		"auth": tag("auth "),
+	},
+}
+
+func TestLoadYAML(t *testing.T) {
+	r := &Router{}
+	err := LoadRegistry(r, []byte(`
+routes:
+  - path: /users/:id
+    methods: [GET]
+    handler: users.show
+    name: user.show
+  - path: /admin
+    handler: admin
+    middleware: [auth]
+  - path: /admin/*
+    handler: admin
+    middleware: [auth]
+  - path: /admin/public
+    handler: admin
+`), testRegistry)
+	assert.Nil(t, err)
+	assert.Equal(t, "show 5", do(r, "GET", "/users/5").Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, do(r, "POST", "/users/5").Code)
+	assert.Equal(t, "auth admin", do(r, "GET", "/admin/x").Body.String())
+	assert.Equal(t, "auth admin", do(r, "GET", "/admin").Body.String())
+	assert.Equal(t, "admin", do(r, "GET", "/admin/public").Body.String())
+	u, _ := r.URL("user.show", 7)
+	assert.Equal(t, "/users/7", u)
+}
+
+func TestLoadJSON(t *testing.T) {
+	r := &Router{}
+	err := Load(r, []byte(`{"routes": [
+		{"path": "/users/:id", "handler": "users.show"}
+	]}`), testRegistry.Handlers)
+	assert.Nil(t, err)
+	assert.Equal(t, "show 5", do(r, "GET", "/users/5").Body.String())
+}
+
+func TestLoadErrors(t *testing.T) {
+	r := &Router{}
+	r.Route("/taken").FuncE(F1)
+	for _, doc := range []string{
+		`routes: [`,
+		`{"routes": [{"path": "/x", "handler": "missing"}]}`,
+		`{"routes": [{"path": "/x", "handler": "admin", "middleware": ["missing"]}]}`,
+		`{"routes": [{"path": "/x//y", "handler": "admin"}]}`,
+		`{"routes": [{"path": "/ok", "handler": "admin"}, {"path": "/taken", "handler": "admin"}]}`,
+	} {
+		assert.NotNil(t, LoadRegistry(r, []byte(doc), testRegistry), doc)
+	}
+	// Nothing from the failed documents was registered.
+	assert.Nil(t, r.lookupPath("/ok", nil))
+}
@@ -0,0 +1,140 @@
+package route
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Locales makes the paths served by r take an optional leading locale
+// segment, one of locales, so that localized sites need no routes of
+// their own per language:
+//
+//     r.Locales("en", "fr", "de")
+//     r.Route("/users/:id").Name("user").FuncE(showUser)
+//
+// serves "/fr/users/1" as "/users/1" with env["locale"] set to "fr".
+// The handler sees the path without the locale.  Requests without a
+// locale segment, like "/users/1" itself, get the locale best matching
+// their Accept-Language header, or failing that the first of locales.
+// Redirects issued by ServeHTTP keep the locale segment.
+//
+// Reverse routing through r, or any node of its tree, then builds
+// locale-prefixed paths: URL uses the first of locales, and LocaleURL
+// and the "localeURL" template function a given one.
+//
+// Locales applies to requests served by r, which should be the root of
+// its tree, before any Rewrite rules.  It is safe to call while
+// serving, and replaces the locales of any earlier call.  It panics if
+// locales is empty or a locale isn't a plain path segment.
+func (r *Router) Locales(locales ...string) {
+	if len(locales) == 0 {
+		panic("route: Locales needs at least one locale")
+	}
+	for _, l := range locales {
+		if l == "" || l == "*" || strings.ContainsAny(l, "/:") {
+			panic(fmt.Sprintf("route: Locales: bad locale %q", l))
+		}
+	}
+	locales = append([]string(nil), locales...)
+	r.locales.Store(&locales)
+}
+
+// splitLocale returns the locale that path starts with, among locales,
+// and the rest of path, or "" and path if it has none.
+func splitLocale(locales []string, path string) (string, string) {
+	seg, _, _ := strings.Cut(path[1:], "/")
+	for _, l := range locales {
+		if seg == l {
+			rest := path[1+len(l):]
+			if rest == "" {
+				rest = "/"
+			}
+			return l, rest
+		}
+	}
+	return "", path
+}
+
+// negotiateLocale returns the entry of locales best matching the
+// Accept-Language header, or the first if none does.  A language
+// range matches a locale equal to it or more specific, as "fr" matches
+// "fr-CA", and, less closely, a locale it is more specific than, as
+// "fr-CH" matches "fr", or one with the same primary language.  The
+// closest match sets the quality, and closer matches win ties, as do
+// earlier locales after that.
+func negotiateLocale(header string, locales []string) string {
+	type langRange struct {
+		tag string
+		q   float64
+	}
+	var ranges []langRange
+	for _, s := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(s, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if qs, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(qs, 64); err == nil {
+				q = f
+			}
+		}
+		ranges = append(ranges, langRange{tag, q})
+	}
+	best, bestQ, bestS := 0, 0.0, -1
+	for i, l := range locales {
+		l = strings.ToLower(l)
+		primary, _, _ := strings.Cut(l, "-")
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			rp, _, _ := strings.Cut(r.tag, "-")
+			s := -1
+			switch {
+			case r.tag == l:
+				s = 4
+			case strings.HasPrefix(l, r.tag+"-"):
+				s = 3
+			case strings.HasPrefix(r.tag, l+"-"):
+				s = 2
+			case rp == primary:
+				s = 1
+			case r.tag == "*":
+				s = 0
+			}
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ || (q > 0 && q == bestQ && specificity > bestS) {
+			best, bestQ, bestS = i, q, specificity
+		}
+	}
+	return locales[best]
+}
+
+// LocaleURL is like URL, but builds the path for the given locale,
+// which must be one of those passed to Locales.
+func (r *Router) LocaleURL(locale, name string, args ...interface{}) (string, error) {
+	path, err := r.url(name, args)
+	if err != nil {
+		return "", err
+	}
+	if ls := r.root().locales.Load(); ls != nil {
+		for _, l := range *ls {
+			if l == locale {
+				return localePath(locale, path), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("route: unknown locale %q", locale)
+}
+
+// localePath returns path prefixed with the segment for locale.
+func localePath(locale, path string) string {
+	if path == "/" {
+		return "/" + locale
+	}
+	return "/" + locale + path
+}
@@ -0,0 +1,73 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocales(t *testing.T) {
+	r := &Router{RedirectTrailingSlash: true}
+	r.Locales("en", "fr", "de")
+	var path string
+	show := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		path = req.URL.Path
+		w.Write([]byte(env["locale"] + " " + env["id"]))
+	}
+	r.Route("/users/:id").Name("user").FuncE(show)
+	r.Route("/").Name("home").FuncE(show)
+	r.Route("/about/").Func(writeString("about"))
+
+	assert.Equal(t, "fr 1", get(r, "/fr/users/1").Body.String())
+	assert.Equal(t, "/users/1", path)
+	assert.Equal(t, "en 2", get(r, "/users/2").Body.String())
+	assert.Equal(t, "de ", get(r, "/de").Body.String())
+	assert.Equal(t, "/", path)
+	assert.Equal(t, "de ", get(r, "/de/").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/es/users/1").Code)
+
+	w := get(r, "/fr/about?x=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/fr/about/?x=1", w.Header().Get("Location"))
+
+	req := httptest.NewRequest("GET", "/users/3", nil)
+	req.Header.Set("Accept-Language", "es, de-AT;q=0.8, fr;q=0.5")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "de 3", w.Body.String())
+
+	url, err := r.URL("user", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, "/en/users/7", url)
+	url, err = r.Route("/users").LocaleURL("fr", "user", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, "/fr/users/7", url)
+	url, err = r.LocaleURL("de", "home")
+	assert.Nil(t, err)
+	assert.Equal(t, "/de", url)
+	_, err = r.LocaleURL("es", "user", 7)
+	assert.NotNil(t, err)
+
+	assert.Panics(t, func() { r.Locales() })
+	assert.Panics(t, func() { r.Locales("en/us") })
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	locales := []string{"en", "fr-CA", "fr", "pt-BR"}
+	for _, tt := range []struct{ header, want string }{
+		{"", "en"},
+		{"ja", "en"},
+		{"fr", "fr"},
+		{"fr-ca", "fr-CA"},
+		{"fr-CH", "fr"},
+		{"pt-PT", "pt-BR"},
+		{"pt", "pt-BR"},
+		{"en;q=0.5, fr;q=0.9", "fr"},
+		{"*;q=0.1, pt-BR;q=0", "en"},
+		{"en;q=0, *", "fr-CA"},
+	} {
+		assert.Equal(t, tt.want, negotiateLocale(tt.header, locales), tt.header)
+	}
+}
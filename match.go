@@ -0,0 +1,73 @@
+package route
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// MatchResult describes the route that would handle a request; see
+// Match.
+type MatchResult struct {
+	// Pattern is the pattern of the matched route, e.g. "/users/:id".
+	Pattern string
+	// Vars holds the values the route captured from the path, as
+	// they would be passed to the handler in env.
+	Vars map[string]string
+	// Name is the name given to the route with Name, if any.
+	Name string
+	// Meta is the route's metadata; see Router.Meta.
+	Meta map[string]interface{}
+	// Handler is the handler that would be called, and HandlerName
+	// the name of the function registered for it.
+	Handler     HandlerE
+	HandlerName string
+}
+
+// Match reports which route would handle a request with the given
+// method and path, without calling anything.  It returns false if no
+// route would.  It is useful for authorization pre-checks, tests and
+// tools that ask what would handle a URL.
+//
+// Variants that depend on request headers (see Header and Accept)
+// are matched as though the request had none.  Serving options like
+// CaseRedirect are not applied.
+func (r *Router) Match(method, path string) (MatchResult, bool) {
+	if path == "" || path[0] != '/' {
+		return MatchResult{}, false
+	}
+	defer r.rlock()()
+	var p params
+	if r.lookup(path, 1, &p) == nil {
+		return MatchResult{}, false
+	}
+	n := p.node
+	if n.alias != nil {
+		// Report the canonical route, which serves the request.
+		n = n.alias
+	}
+	if len(n.variants) > 0 {
+		req := &http.Request{Method: method, URL: &url.URL{Path: path}, Header: http.Header{}}
+		if _, n, _ = n.selectHandler(req); n == nil {
+			return MatchResult{}, false
+		}
+	}
+	if n.handler == nil {
+		return MatchResult{}, false
+	}
+	m := MatchResult{
+		Pattern:     n.displayPattern(),
+		Name:        n.name,
+		Meta:        n.meta,
+		Handler:     n.handler,
+		HandlerName: n.handlerName,
+	}
+	// A name given to a path applies to all its variants.
+	for v := n; m.Name == "" && v.match != nil; v = v.parent {
+		m.Name = v.parent.name
+	}
+	if p.n > 0 {
+		m.Vars = map[string]string{}
+		p.fill(m.Vars)
+	}
+	return m, true
+}
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	r := &Router{}
+	r.Route("/").FuncE(F1)
+	user := r.Route("/users/:id").Name("user.show")
+	user.Method("GET").FuncE(writeEnv("show"))
+	user.Method("PUT").FuncE(writeEnv("update"))
+	r.Route("/static/*").SetMeta("cache", true).FuncE(F1)
+
+	m, ok := r.Match("PUT", "/users/5")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/:id", m.Pattern)
+	assert.Equal(t, map[string]string{"id": "5"}, m.Vars)
+	assert.Equal(t, "user.show", m.Name)
+	assert.NotNil(t, m.Handler)
+	assert.Equal(t, "github.com/evmar/route.writeEnv.func1", m.HandlerName)
+
+	m, ok = r.Match("HEAD", "/users/5")
+	assert.True(t, ok)
+
+	m, ok = r.Match("GET", "/")
+	assert.True(t, ok)
+	assert.Equal(t, "/", m.Pattern)
+	assert.Nil(t, m.Vars)
+
+	m, ok = r.Match("GET", "/static/a/b.css")
+	assert.True(t, ok)
+	assert.Equal(t, "/static/*", m.Pattern)
+	assert.Equal(t, "a/b.css", m.Vars["*"])
+	assert.Equal(t, true, m.Meta["cache"])
+
+	_, ok = r.Match("DELETE", "/users/5")
+	assert.False(t, ok)
+	_, ok = r.Match("GET", "/missing")
+	assert.False(t, ok)
+	_, ok = r.Match("GET", "relative")
+	assert.False(t, ok)
+}
@@ -0,0 +1,189 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Merge grafts the routes of other into r beneath prefix, so that
+// sub-routers built separately can be composed into one tree:
+//
+//     api := &route.Router{}
+//     api.Route("/users/:id").FuncE(showUser)
+//     r.Merge(api, "/api") // Serves "/api/users/:id".
+//
+// The routes are copied, so later changes to other don't affect r.
+// An empty prefix merges at r itself.  Merge returns an error,
+// leaving r unchanged, if other conflicts with existing routes:
+// handlers or names at the same point, names used elsewhere in r's
+// tree, or differently named variables.
+// other must not be part of r's tree.
+func (r *Router) Merge(other *Router, prefix string) error {
+	if r.root() == other.root() {
+		return errors.New("route: can't merge a router into its own tree")
+	}
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer other.rlock()()
+	if r.isFrozen() {
+		return ErrFrozen
+	}
+	if err := other.walk(func(n *Router) error {
+		if d := r.root().find(n.name); n.name != "" && d != nil {
+			return fmt.Errorf("%s: name %q is already used by %s", n.pattern, n.name, d.pattern)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// Find the node at prefix without creating it, so that a failed
+	// merge leaves nothing behind; a new node has nothing to conflict
+	// with.
+	dst := r
+	if prefix != "" && prefix != "/" {
+		if dst, err = r.findRoute(prefix); err != nil {
+			return err
+		}
+	}
+	if dst == nil {
+		dst, err = r.routePath(prefix)
+	} else {
+		err = merge(dst, other, false)
+	}
+	if err != nil {
+		return err
+	}
+	if err := merge(dst, other, true); err != nil {
+		return err
+	}
+	dst.relinkAliases(strings.TrimSuffix(dst.pattern, other.pattern))
+	return nil
+}
+
+// merge merges src into dst.  If apply is false it only checks for
+// conflicts, without modifying dst.
+func merge(dst, src *Router, apply bool) error {
+	if apply {
+		dst.invalidate()
+	}
+	if src.handler != nil {
+		if dst.handler != nil {
+			return fmt.Errorf("%s: duplicate handler", dst.pattern)
+		}
+		if apply {
+			dst.handler = src.handler
+			dst.handlerName = src.handlerName
+			if src.bind != nil {
+				dst.handler, dst.bind = src.bind(dst), src.bind
+			}
+			dst.alias = src.alias
+		}
+	}
+	if src.name != "" {
+		if dst.name != "" {
+			return fmt.Errorf("%s: duplicate name", dst.pattern)
+		}
+		if apply {
+			dst.name = src.name
+		}
+	}
+	if apply {
+		dst.middleware = append(dst.middleware, src.middleware...)
+		if dst.notFound == nil {
+			dst.notFound = src.notFound
+		}
+		if dst.methodNotAllowed == nil {
+			dst.methodNotAllowed = src.methodNotAllowed
+		}
+		if dst.errorHandler == nil {
+			dst.errorHandler = src.errorHandler
+		}
+		if dst.assets == nil {
+			dst.assets = src.assets
+		}
+		if dst.templates == nil {
+			dst.templates = src.templates
+		}
+		if dst.maxBody == 0 && src.maxBody != 0 {
+			dst.maxBody = src.maxBody
+			dst.root().bodyLimits.Store(true)
+		}
+		for k, v := range src.meta {
+			if dst.meta == nil {
+				dst.meta = make(map[string]interface{})
+			}
+			if _, ok := dst.meta[k]; !ok {
+				dst.meta[k] = v
+			}
+		}
+	}
+
+	for _, v := range src.variants {
+		cond := v.conds[len(v.conds)-1]
+		var d *Router
+		for _, dv := range dst.variants {
+			if dv.conds[len(dv.conds)-1] == cond {
+				d = dv
+			}
+		}
+		if d == nil {
+			if !apply {
+				continue
+			}
+			d = dst.variant(cond, v.match)
+			d.accept = v.accept
+			d.mismatch = v.mismatch
+			d.method = v.method
+			d.vary = v.vary
+		}
+		if err := merge(d, v, apply); err != nil {
+			return err
+		}
+	}
+
+	for k, m := range src.matchers {
+		d := dst.matchers[k]
+		if d == nil {
+			if !apply {
+				continue
+			}
+			if dst.matchers == nil {
+				dst.matchers = make(map[string]*Router)
+			}
+			d = dst.child(k)
+			dst.matchers[k] = d
+		}
+		if err := merge(d, m, apply); err != nil {
+			return err
+		}
+	}
+	if src.varRouter != nil {
+		if dst.varRouter != nil && dst.varName != src.varName {
+			return fmt.Errorf("%s: overlapping vars: %q / %q", dst.pattern, dst.varName, src.varName)
+		}
+		if dst.varRouter == nil && apply {
+			dst.varName = src.varName
+			dst.varRouter = dst.child(":" + src.varName)
+		}
+		if dst.varRouter != nil {
+			if err := merge(dst.varRouter, src.varRouter, apply); err != nil {
+				return err
+			}
+		}
+	}
+	if src.fallbackRouter != nil {
+		if dst.fallbackRouter == nil && apply {
+			dst.fallbackRouter = dst.child("*")
+		}
+		if dst.fallbackRouter != nil {
+			if err := merge(dst.fallbackRouter, src.fallbackRouter, apply); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	api := &Router{}
+	api.Route("/users/:id").Name("user").FuncE(F1)
+	api.Route("/files/*").FuncE(F1)
+
+	r := &Router{}
+	r.Route("/").FuncE(F1)
+	r.Route("/api/health").FuncE(F1)
+	assert.Nil(t, r.Merge(api, "/api"))
+
+	var patterns []string
+	for _, ri := range r.Routes() {
+		patterns = append(patterns, ri.Pattern)
+	}
+	assert.Equal(t, []string{"/", "/api/files/*", "/api/health", "/api/users/:id"}, patterns)
+
+	u, err := r.URL("user", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "/api/users/5", u)
+
+	// Copied, not shared.
+	api.Route("/later").FuncE(F1)
+	assert.Nil(t, r.lookupPath("/api/later", nil))
+}
+
+func TestMergeConflict(t *testing.T) {
+	r := &Router{}
+	r.Route("/api/users/:id").FuncE(F1)
+
+	other := &Router{}
+	other.Route("/new").FuncE(F1)
+	other.Route("/users/:name").FuncE(F1)
+	assert.NotNil(t, r.Merge(other, "/api"))
+	// Nothing was merged.
+	assert.Nil(t, r.lookupPath("/api/new", nil))
+
+	other = &Router{}
+	other.Route("/api/users/:id").FuncE(F1)
+	assert.NotNil(t, r.Merge(other, ""))
+
+	// A failed merge beneath a new prefix leaves no nodes behind.
+	r = &Router{Strict: true}
+	r.Route("/v1").Name("v1").FuncE(F1)
+	other = &Router{}
+	other.Route("/users").Name("v1").FuncE(F1)
+	assert.NotNil(t, r.Merge(other, "/v2/beta"))
+	other = &Router{}
+	other.Route("/users").FuncE(F1)
+	assert.NotNil(t, r.Merge(other, "/v2/beta//x"))
+	assert.NotContains(t, r.DumpString(), "/v2")
+}
+
+func TestMergeSameTree(t *testing.T) {
+	r := &Router{}
+	r.Route("/v1/users").FuncE(F1)
+	assert.NotNil(t, r.Merge(r.Route("/v1"), "/v2"))
+	assert.Nil(t, r.lookupPath("/v2/users", nil))
+}
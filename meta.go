@@ -0,0 +1,29 @@
+package route
+
+// Meta returns a copy of the metadata attached to the current point in
+// the tree, which SetMeta adds to.  The router itself doesn't
+// interpret metadata; it's a place to hang per-route information
+// (documentation summaries, tags, required permissions) for tools and
+// middleware that inspect the tree.
+func (r *Router) Meta() map[string]interface{} {
+	defer r.rlock()()
+	meta := make(map[string]interface{}, len(r.meta))
+	for k, v := range r.meta {
+		meta[k] = v
+	}
+	return meta
+}
+
+// SetMeta sets a single metadata key on the current point and returns
+// the router, to allow chaining before registering a handler, as in:
+//
+//     r.Route("/users/:id").SetMeta("summary", "Show a user").FuncE(showUser)
+func (r *Router) SetMeta(key string, value interface{}) *Router {
+	defer r.lock()()
+	r.mustNotBeFrozen()
+	if r.meta == nil {
+		r.meta = make(map[string]interface{})
+	}
+	r.meta[key] = value
+	return r
+}
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeta(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").SetMeta("summary", "Show a user").FuncE(F1)
+	r.Route("/users/:id").SetMeta("tags", []string{"users"})
+	r.Route("/users/:id").Meta()["summary"] = "changed"
+
+	routes := r.Routes()
+	assert.Equal(t, 1, len(routes))
+	assert.Equal(t, "Show a user", routes[0].Meta["summary"])
+	assert.Equal(t, []string{"users"}, routes[0].Meta["tags"])
+
+	assert.Equal(t, 0, len(r.Route("/other").Meta()))
+}
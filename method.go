@@ -0,0 +1,107 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Method returns a variant of the current point that only accepts
+// requests with the given HTTP method.  A GET variant also accepts
+// HEAD requests.
+//
+//     user := r.Route("/users/:id")
+//     user.Method("GET").FuncE(showUser)
+//     user.Method("PUT").FuncE(updateUser)
+//
+// A request whose method matches no variant is handled by the handler
+// registered on the path itself, if any; otherwise it gets a 405
+// Method Not Allowed (see MethodNotAllowed).  See Header for more on
+// variants.
+func (r *Router) Method(method string) *Router {
+	defer r.lock()()
+	method = strings.ToUpper(method)
+	v := r.variant("Method: "+method, func(req *http.Request) bool {
+		return req.Method == method || (method == http.MethodGet && req.Method == http.MethodHead)
+	})
+	v.method = method
+	v.mismatch = http.StatusMethodNotAllowed
+	return v
+}
+
+// Any registers f to handle requests to the current point with any
+// method not claimed by a Method variant.  It's the same as FuncE, but
+// reads better when method routing is being introduced gradually:
+//
+//     users := r.Route("/users")
+//     users.Any(legacyUsers)              // Everything else, as before.
+//     users.Method("POST").FuncE(create)  // Moved to its own handler.
+//
+// Because f accepts every method, requests to the point never get a
+// 405 Method Not Allowed.
+func (r *Router) Any(f func(w http.ResponseWriter, r *http.Request, env map[string]string)) {
+	r.FuncE(f)
+}
+
+// methods returns the methods r is restricted to, or nil for any.
+func (r *Router) methods() []string {
+	if r.method == "" {
+		return nil
+	}
+	return []string{r.method}
+}
+
+// allowedMethods lists the methods accepted by r's variants, in
+// registration order, for the Allow header.
+func (r *Router) allowedMethods() []string {
+	var allowed []string
+	seen := map[string]bool{}
+	add := func(m string) {
+		if !seen[m] {
+			seen[m] = true
+			allowed = append(allowed, m)
+		}
+	}
+	var visit func(n *Router)
+	visit = func(n *Router) {
+		if n.method != "" && n.handler != nil {
+			add(n.method)
+			if n.method == http.MethodGet {
+				add(http.MethodHead)
+			}
+		}
+		for _, v := range n.variants {
+			visit(v)
+		}
+	}
+	visit(r)
+	return allowed
+}
+
+// MethodNotAllowed sets the handler for requests at or beneath the
+// current point that match a route's path but none of its methods, in
+// place of a plain 405 response.  The handler receives the methods the
+// route does allow; the Allow header is already set when it is called.
+// As with NotFound, a handler set on a subtree overrides one set
+// nearer the root.
+func (r *Router) MethodNotAllowed(f func(w http.ResponseWriter, r *http.Request, allowed []string)) {
+	defer r.lock()()
+	r.mustNotBeFrozen()
+	r.methodNotAllowed = f
+}
+
+// methodNotAllowedHandler returns the handler for a request to r that
+// matched none of the allowed methods.
+func (r *Router) methodNotAllowedHandler(allowed []string) http.HandlerFunc {
+	var f func(w http.ResponseWriter, r *http.Request, allowed []string)
+	for n := r; n != nil && f == nil; n = n.parent {
+		f = n.methodNotAllowed
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		if f != nil {
+			f(w, req, allowed)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,74 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func do(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMethod(t *testing.T) {
+	r := &Router{}
+	user := r.Route("/users/:id")
+	user.Method("GET").Func(writeString("show"))
+	user.Method("put").Func(writeString("update"))
+
+	assert.Equal(t, "show", do(r, "GET", "/users/5").Body.String())
+	assert.Equal(t, http.StatusOK, do(r, "HEAD", "/users/5").Code)
+	assert.Equal(t, "update", do(r, "PUT", "/users/5").Body.String())
+
+	w := do(r, "DELETE", "/users/5")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, PUT", w.Header().Get("Allow"))
+
+	var patterns []string
+	r.Walk(func(pattern string, methods []string, h HandlerE) error {
+		patterns = append(patterns, pattern+" "+strings.Join(methods, ","))
+		return nil
+	})
+	assert.Equal(t, []string{"/users/:id GET", "/users/:id PUT"}, patterns)
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	r := &Router{}
+	r.Route("/a").Method("POST").Func(writeString("post"))
+	r.Route("/api/b").Method("POST").Func(writeString("post"))
+	r.Route("/api").MethodNotAllowed(func(w http.ResponseWriter, r *http.Request, allowed []string) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		w.Write([]byte(`{"allowed":"` + strings.Join(allowed, ",") + `"}`))
+	})
+
+	w := do(r, "GET", "/a")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Allow"))
+
+	w = do(r, "GET", "/api/b")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Allow"))
+	assert.Equal(t, `{"allowed":"POST"}`, w.Body.String())
+
+	// Methods of routes beneath the path aren't allowed on it.
+	r.Route("/users").Method("GET").Func(writeString("list"))
+	r.Route("/users/:id").Method("DELETE").Func(writeString("delete"))
+	assert.Equal(t, "GET, HEAD", do(r, "POST", "/users").Header().Get("Allow"))
+}
+
+func TestAny(t *testing.T) {
+	r := &Router{}
+	users := r.Route("/users")
+	users.Any(writeEnv("any"))
+	users.Method("POST").FuncE(writeEnv("post"))
+
+	assert.Equal(t, "post", do(r, "POST", "/users").Body.String())
+	assert.Equal(t, "any", do(r, "GET", "/users").Body.String())
+	assert.Equal(t, "any", do(r, "DELETE", "/users").Body.String())
+	assert.Panics(t, func() { users.Any(writeEnv("again")) })
+}
@@ -0,0 +1,219 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of
+// Metrics' duration histogram, unless Buckets is set.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics collects request metrics for Prometheus, labeled by the
+// matched route's pattern (see Pattern) rather than the raw path, so
+// dashboards aren't swamped by one series per user id:
+//
+//     var metrics route.Metrics
+//     r.Use(metrics.Middleware())
+//     r.Route("/metrics").Func(metrics.ServeHTTP)
+//
+// It exports http_requests_total and the histogram
+// http_request_duration_seconds, labeled by route, method and status,
+// and the gauge http_requests_in_flight, labeled by route and method,
+// in the Prometheus text format.  Nonstandard methods are labeled
+// "OTHER", so that clients can't create series at will.  Requests
+// matching no route aren't counted.  The zero Metrics is ready to use;
+// it must not be copied after first use.
+type Metrics struct {
+	// Buckets are the upper bounds, in seconds, of the duration
+	// histogram's buckets, in increasing order.  If nil,
+	// DefaultBuckets is used.  Set it before first use.
+	Buckets []float64
+
+	mu       sync.Mutex
+	series   map[metricKey]*metricSeries
+	inFlight map[metricKey]int
+}
+
+// metricKey is a set of label values; status is zero for in-flight
+// requests.
+type metricKey struct {
+	route, method string
+	status        int
+}
+
+// metricSeries holds the counts for one metricKey.  buckets counts
+// durations falling in each bucket, the last being +Inf.
+type metricSeries struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+func (m *Metrics) buckets() []float64 {
+	if m.Buckets != nil {
+		return m.Buckets
+	}
+	return DefaultBuckets
+}
+
+// Middleware returns middleware recording requests in m.
+func (m *Metrics) Middleware() Middleware {
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			flight := metricKey{route: Pattern(req), method: methodLabel(req.Method)}
+			m.mu.Lock()
+			if m.inFlight == nil {
+				m.inFlight = map[metricKey]int{}
+			}
+			m.inFlight[flight]++
+			m.mu.Unlock()
+			defer func() {
+				m.mu.Lock()
+				m.inFlight[flight]--
+				m.mu.Unlock()
+			}()
+
+			start := timeNow()
+			sw := &statusWriter{ResponseWriter: w}
+			next(sw, req, env)
+			key := flight
+			key.status = sw.code
+			if key.status == 0 {
+				key.status = http.StatusOK
+			}
+			m.observe(key, timeNow().Sub(start).Seconds())
+		}
+	}
+}
+
+// methodLabel returns the label for the request method m.
+func methodLabel(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return m
+	}
+	return "OTHER"
+}
+
+// observe records a request taking secs.
+func (m *Metrics) observe(key metricKey, secs float64) {
+	bounds := m.buckets()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.series == nil {
+		m.series = map[metricKey]*metricSeries{}
+	}
+	s := m.series[key]
+	if s == nil {
+		s = &metricSeries{buckets: make([]uint64, len(bounds)+1)}
+		m.series[key] = s
+	}
+	s.count++
+	s.sum += secs
+	s.buckets[sort.SearchFloat64s(bounds, secs)]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	bounds := m.buckets()
+	m.mu.Lock()
+	keys := make([]metricKey, 0, len(m.series))
+	series := make(map[metricKey]metricSeries, len(m.series))
+	for k, s := range m.series {
+		keys = append(keys, k)
+		series[k] = metricSeries{s.count, s.sum, append([]uint64(nil), s.buckets...)}
+	}
+	var flying []metricKey
+	inFlight := make(map[metricKey]int, len(m.inFlight))
+	for k, n := range m.inFlight {
+		flying = append(flying, k)
+		inFlight[k] = n
+	}
+	m.mu.Unlock()
+	sortMetricKeys(keys)
+	sortMetricKeys(flying)
+
+	var b strings.Builder
+	b.WriteString("# HELP http_requests_total Requests served, by route pattern, method and status.\n")
+	b.WriteString("# TYPE http_requests_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "http_requests_total{%s} %d\n", k.labels(), series[k].count)
+	}
+	b.WriteString("# HELP http_request_duration_seconds Time taken to serve requests, by route pattern, method and status.\n")
+	b.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, k := range keys {
+		s := series[k]
+		var n uint64
+		for i, c := range s.buckets {
+			n += c
+			le := "+Inf"
+			if i < len(bounds) {
+				le = strconv.FormatFloat(bounds[i], 'g', -1, 64)
+			}
+			fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=%q} %d\n", k.labels(), le, n)
+		}
+		fmt.Fprintf(&b, "http_request_duration_seconds_sum{%s} %s\n", k.labels(), strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "http_request_duration_seconds_count{%s} %d\n", k.labels(), s.count)
+	}
+	b.WriteString("# HELP http_requests_in_flight Requests being served, by route pattern and method.\n")
+	b.WriteString("# TYPE http_requests_in_flight gauge\n")
+	for _, k := range flying {
+		fmt.Fprintf(&b, "http_requests_in_flight{%s} %d\n", k.labels(), inFlight[k])
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+func sortMetricKeys(keys []metricKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats k as Prometheus labels, omitting a zero status.
+func (k metricKey) labels() string {
+	s := `method="` + labelEscaper.Replace(k.method) + `",route="` + labelEscaper.Replace(k.route) + `"`
+	if k.status != 0 {
+		s += `,status="` + strconv.Itoa(k.status) + `"`
+	}
+	return s
+}
+
+// statusWriter records the status of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if sw.code == 0 {
+		sw.code = code
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	if sw.code == 0 {
+		sw.code = http.StatusOK
+	}
+	return sw.ResponseWriter.Write(p)
+}
+
+func (sw *statusWriter) Unwrap() http.ResponseWriter { return sw.ResponseWriter }
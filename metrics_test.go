@@ -0,0 +1,70 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	now := fakeClock(t)
+	m := &Metrics{Buckets: []float64{0.1, 1}}
+	r := &Router{}
+	r.Use(m.Middleware())
+	r.Route("/users/:id").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		if env["id"] == "0" {
+			http.NotFound(w, req)
+			return
+		}
+		*now = now.Add(500 * time.Millisecond)
+	})
+	r.Route("/metrics").Func(m.ServeHTTP)
+
+	get(r, "/users/1")
+	get(r, "/users/2")
+	get(r, "/users/0")
+	get(r, "/nowhere")
+
+	w := get(r, "/metrics")
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP http_requests_total Requests served, by route pattern, method and status.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/users/:id",status="200"} 2
+http_requests_total{method="GET",route="/users/:id",status="404"} 1
+# HELP http_request_duration_seconds Time taken to serve requests, by route pattern, method and status.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="200",le="0.1"} 0
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="200",le="1"} 2
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="200",le="+Inf"} 2
+http_request_duration_seconds_sum{method="GET",route="/users/:id",status="200"} 1
+http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 2
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="404",le="0.1"} 1
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="404",le="1"} 1
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="404",le="+Inf"} 1
+http_request_duration_seconds_sum{method="GET",route="/users/:id",status="404"} 0
+http_request_duration_seconds_count{method="GET",route="/users/:id",status="404"} 1
+# HELP http_requests_in_flight Requests being served, by route pattern and method.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight{method="GET",route="/metrics"} 1
+http_requests_in_flight{method="GET",route="/users/:id"} 0
+`, w.Body.String())
+}
+
+func TestMetricsMethodLabel(t *testing.T) {
+	m := &Metrics{}
+	r := &Router{}
+	r.Use(m.Middleware())
+	r.Route("/x").FuncE(F1)
+	r.Route("/metrics").Func(m.ServeHTTP)
+
+	do(r, "DELETE", "/x")
+	do(r, "BREW", "/x")
+	do(r, "XYZZY", "/x")
+
+	body := get(r, "/metrics").Body.String()
+	assert.Contains(t, body, `http_requests_total{method="DELETE",route="/x",status="200"} 1`)
+	assert.Contains(t, body, `http_requests_total{method="OTHER",route="/x",status="200"} 2`)
+	assert.NotContains(t, body, "BREW")
+}
@@ -0,0 +1,103 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Mount dispatches every path at or beneath the current point to h,
+// for embedding existing handlers (a file server, pprof, another
+// router) under a prefix.  For example, after
+//
+//     r.Route("/admin").Mount(adminHandler)
+//
+// "/admin", "/admin/" and "/admin/users/5" are all served by adminHandler,
+// with the request path left unchanged.  The part of the path beneath
+// the mount point is available in env["*"].
+func (r *Router) Mount(h http.Handler) {
+	r.mount(h, false)
+}
+
+// MountStripPrefix is like Mount, but strips the mount prefix from the
+// request's URL.Path before calling h, in the manner of
+// http.StripPrefix, so "/admin/users/5" is seen by h as "/users/5".
+func (r *Router) MountStripPrefix(h http.Handler) {
+	r.mount(h, true)
+}
+
+func (r *Router) mount(h http.Handler, strip bool) {
+	defer r.lock()()
+	f := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		if strip {
+			req = stripRequest(req, "/"+env["*"])
+		}
+		h.ServeHTTP(w, req)
+	}
+	// Check both registrations before making either, so that a
+	// failure leaves no half-mounted handler behind.
+	if r.handler != nil {
+		panic("duplicate handler")
+	}
+	if _, err := r.findRoute("*"); err != nil {
+		panic(err.Error())
+	}
+	name := handlerName(h)
+	if err := r.setHandler(f, name); err != nil {
+		panic(err.Error())
+	}
+	fallback, err := r.routePath("*")
+	if err != nil {
+		panic(err.Error())
+	}
+	if err := fallback.setHandler(f, name); err != nil {
+		panic(err.Error())
+	}
+}
+
+// handlerName returns the name of an http.Handler, for display.
+func handlerName(h http.Handler) string {
+	if f, ok := h.(http.HandlerFunc); ok {
+		return funcName(f)
+	}
+	return fmt.Sprintf("%T", h)
+}
+
+// stripRequest returns a shallow copy of req with its path replaced.
+func stripRequest(req *http.Request, path string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.URL = new(url.URL)
+	*r2.URL = *req.URL
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+	return r2
+}
+
+// Handle registers h for the given pattern, like http.ServeMux.Handle,
+// to ease migrating from ServeMux:
+//
+//     r.Handle("/metrics", metricsHandler)
+//
+// is shorthand for r.Route("/metrics").Func(metricsHandler.ServeHTTP).
+// Note that patterns use this package's syntax: unlike ServeMux, a
+// trailing slash matches only that exact path, so ServeMux's
+// "/static/" subtree pattern is written "/static/*" here.
+func (r *Router) Handle(pattern string, h http.Handler) {
+	defer r.lock()()
+	r2, err := r.routePath(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+	if err := r2.setHandler(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		h.ServeHTTP(w, req)
+	}, handlerName(h)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// HandleFunc registers f for the given pattern, like
+// http.ServeMux.HandleFunc.  See Handle.
+func (r *Router) HandleFunc(pattern string, f func(http.ResponseWriter, *http.Request)) {
+	r.Route(pattern).Func(f)
+}
@@ -0,0 +1,70 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func echoPath(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(r.URL.Path))
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestMount(t *testing.T) {
+	r := &Router{}
+	r.Route("/admin").Mount(http.HandlerFunc(echoPath))
+	r.Route("/sub").MountStripPrefix(http.HandlerFunc(echoPath))
+
+	assert.Equal(t, "/admin", get(r, "/admin").Body.String())
+	assert.Equal(t, "/admin/", get(r, "/admin/").Body.String())
+	assert.Equal(t, "/admin/users/5", get(r, "/admin/users/5").Body.String())
+
+	assert.Equal(t, "/", get(r, "/sub").Body.String())
+	assert.Equal(t, "/", get(r, "/sub/").Body.String())
+	assert.Equal(t, "/users/5", get(r, "/sub/users/5").Body.String())
+
+	assert.Equal(t, http.StatusNotFound, get(r, "/other").Code)
+}
+
+type pathHandler struct{}
+
+func (pathHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) { echoPath(w, r) }
+
+func TestMountConflict(t *testing.T) {
+	r := &Router{}
+	r.Route("/files/*").Func(writeString("files"))
+	assert.Panics(t, func() { r.Route("/files").Mount(http.HandlerFunc(echoPath)) })
+	// Nothing was mounted at /files itself.
+	assert.Equal(t, http.StatusNotFound, get(r, "/files").Code)
+	assert.Equal(t, "files", get(r, "/files/x").Body.String())
+}
+
+func TestMountName(t *testing.T) {
+	r := &Router{}
+	r.Route("/a").Mount(http.HandlerFunc(echoPath))
+	r.Route("/b").Mount(pathHandler{})
+	routes := r.Routes()
+	assert.Equal(t, "github.com/evmar/route.echoPath", routes[0].HandlerName)
+	assert.Equal(t, "route.pathHandler", routes[2].HandlerName)
+}
+
+func TestHandle(t *testing.T) {
+	r := &Router{}
+	r.Handle("/a", pathHandler{})
+	r.HandleFunc("/b/:id", echoPath)
+	r.Handle("/static/*", http.StripPrefix("/static", pathHandler{}))
+
+	assert.Equal(t, "/a", get(r, "/a").Body.String())
+	assert.Equal(t, "/b/5", get(r, "/b/5").Body.String())
+	assert.Equal(t, "/x/y", get(r, "/static/x/y").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/a/").Code)
+	assert.Panics(t, func() { r.Handle("/a", pathHandler{}) })
+}
@@ -0,0 +1,138 @@
+package route
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Accept returns a variant of the current point that produces the given
+// media type, like "application/json".  Requests for the path are
+// dispatched among the Accept variants by content negotiation against
+// the request's Accept header, preferring earlier registrations when
+// the client has no preference:
+//
+//     report := r.Route("/report")
+//     report.Accept("application/json").FuncE(jsonReport)
+//     report.Accept("text/csv").FuncE(csvReport)
+//
+// If the client accepts none of the registered types, the handler
+// registered on the path itself is used, or failing that the request
+// gets a 406 Not Acceptable.  See Header for more on variants.
+func (r *Router) Accept(mediaType string) *Router {
+	defer r.lock()()
+	mediaType = strings.ToLower(mediaType)
+	v := r.variant("Accept: "+mediaType, func(req *http.Request) bool { return true })
+	v.accept = mediaType
+	v.vary = "Accept"
+	return v
+}
+
+// acceptRange is one entry in an Accept header.
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, s := range strings.Split(header, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(s)
+		if err != nil {
+			if !strings.HasPrefix(s, "*") {
+				continue
+			}
+			// Tolerate the common but invalid bare "*".
+			mt = "*/*"
+		}
+		typ, subtype, ok := strings.Cut(mt, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(qs, 64); err == nil {
+				q = f
+			}
+		}
+		ranges = append(ranges, acceptRange{typ, subtype, q})
+	}
+	return ranges
+}
+
+// quality returns the quality the Accept ranges give to mediaType,
+// using the most specific matching range.
+func quality(ranges []acceptRange, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+// negotiate returns the index of the entry of offers best matching the
+// Accept header, or -1 if none are acceptable.  An empty header
+// accepts anything, so selects the first offer.
+func negotiate(header string, offers []string) int {
+	if strings.TrimSpace(header) == "" {
+		if len(offers) == 0 {
+			return -1
+		}
+		return 0
+	}
+	ranges := parseAccept(header)
+	best, bestQ := -1, 0.0
+	for i, offer := range offers {
+		if q := quality(ranges, offer); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	return best
+}
+
+// ContentType returns a variant of the current point that only accepts
+// request bodies of the given media type, so that, for example, JSON and
+// form submissions to one path can be handled separately:
+//
+//     submit := r.Route("/submit")
+//     submit.ContentType("application/json").FuncE(submitJSON)
+//     submit.ContentType("multipart/*").FuncE(submitMultipart)
+//
+// The subtype may be "*" to match any subtype.  Parameters of the
+// request's Content-Type, like charset, are ignored.  A request whose
+// Content-Type matches no variant is handled by the handler registered
+// on the path itself, or failing that gets a 415 Unsupported Media Type.
+// See Header for more on variants.
+func (r *Router) ContentType(mediaType string) *Router {
+	defer r.lock()()
+	mediaType = strings.ToLower(mediaType)
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	v := r.variant("Content-Type: "+mediaType, func(req *http.Request) bool {
+		mt, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil {
+			return false
+		}
+		t, st, _ := strings.Cut(mt, "/")
+		return t == typ && (subtype == "*" || st == subtype)
+	})
+	v.mismatch = http.StatusUnsupportedMediaType
+	v.vary = "Content-Type"
+	return v
+}
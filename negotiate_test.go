@@ -0,0 +1,55 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/csv"}
+	assert.Equal(t, 0, negotiate("", offers))
+	assert.Equal(t, 0, negotiate("*/*", offers))
+	assert.Equal(t, 1, negotiate("text/csv", offers))
+	assert.Equal(t, 1, negotiate("text/*", offers))
+	assert.Equal(t, 1, negotiate("application/json;q=0.5, text/csv", offers))
+	assert.Equal(t, 0, negotiate("text/csv;q=0.1, */*;q=0.9", offers))
+	assert.Equal(t, 1, negotiate("application/json;q=0, */*", offers))
+	assert.Equal(t, -1, negotiate("image/png", offers))
+	assert.Equal(t, -1, negotiate("*/*", nil))
+}
+
+func TestAccept(t *testing.T) {
+	r := &Router{}
+	report := r.Route("/report")
+	report.Accept("application/json").Func(writeString("json"))
+	report.Accept("text/csv").Func(writeString("csv"))
+
+	assert.Equal(t, "json", withHeader(r, "/report", "", "").Body.String())
+	assert.Equal(t, "json", withHeader(r, "/report", "Accept", "application/json").Body.String())
+	assert.Equal(t, "csv", withHeader(r, "/report", "Accept", "text/csv, application/json;q=0.8").Body.String())
+	assert.Equal(t, http.StatusNotAcceptable, withHeader(r, "/report", "Accept", "image/png").Code)
+
+	report.Func(writeString("default"))
+	w := withHeader(r, "/report", "Accept", "image/png")
+	assert.Equal(t, "default", w.Body.String())
+	assert.Equal(t, []string{"Accept"}, w.Header().Values("Vary"))
+	assert.Equal(t, []string{"Accept"}, withHeader(r, "/report", "", "").Header().Values("Vary"))
+}
+
+func TestContentType(t *testing.T) {
+	r := &Router{}
+	submit := r.Route("/submit")
+	submit.ContentType("application/json").Func(writeString("json"))
+	submit.ContentType("multipart/*").Func(writeString("multipart"))
+
+	assert.Equal(t, "json", withHeader(r, "/submit", "Content-Type", "application/json; charset=utf-8").Body.String())
+	assert.Equal(t, "multipart", withHeader(r, "/submit", "Content-Type", "multipart/form-data; boundary=x").Body.String())
+	assert.Equal(t, http.StatusUnsupportedMediaType, withHeader(r, "/submit", "Content-Type", "text/plain").Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, withHeader(r, "/submit", "", "").Code)
+
+	submit.Func(writeString("default"))
+	assert.Equal(t, "default", withHeader(r, "/submit", "Content-Type", "text/plain").Body.String())
+	assert.Equal(t, "Content-Type", withHeader(r, "/submit", "", "").Header().Get("Vary"))
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NotFound sets the handler for requests at or beneath the current
+// point that match no route, in place of http.NotFound.  Set it on the
+// root for the whole tree; setting it on a subtree, like "/api",
+// overrides it there:
+//
+//     r.NotFound(htmlNotFound)
+//     r.Route("/api").NotFound(jsonNotFound)
+func (r *Router) NotFound(f func(http.ResponseWriter, *http.Request)) {
+	defer r.lock()()
+	r.mustNotBeFrozen()
+	r.notFound = f
+}
+
+// notFoundHandler returns the not-found handler for r, inherited from
+// its nearest ancestor that has one.
+func (r *Router) notFoundHandler() http.HandlerFunc {
+	for n := r; n != nil; n = n.parent {
+		if n.notFound != nil {
+			return n.notFound
+		}
+	}
+	return http.NotFound
+}
+
+// notFoundAt returns the not-found handler for a path that matched no
+// route, from the deepest node the path reaches.
+func (r *Router) notFoundAt(path string) http.HandlerFunc {
+	defer r.rlock()()
+	n := r
+	for i := 1; i <= len(path); {
+		comp, next := segment(path, i)
+		if n.CaseInsensitive {
+			comp = strings.ToLower(comp)
+		}
+		if m := n.matchers[comp]; m != nil {
+			n = m
+		} else if comp != "" && n.varRouter != nil {
+			n = n.varRouter
+		} else if n.fallbackRouter != nil {
+			n = n.fallbackRouter
+			break
+		} else {
+			break
+		}
+		i = next
+	}
+	return n.notFoundHandler()
+}
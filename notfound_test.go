@@ -0,0 +1,27 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFound(t *testing.T) {
+	r := &Router{}
+	r.Route("/").Func(writeString("home"))
+	r.Route("/api/users/:id").Func(writeString("user"))
+	r.Route("/hook").Header("X-Event", "push").Func(writeString("push"))
+
+	assert.Equal(t, http.StatusNotFound, get(r, "/nope").Code)
+
+	r.NotFound(writeString("site 404"))
+	r.Route("/api").NotFound(writeString("api 404"))
+
+	assert.Equal(t, "site 404", get(r, "/nope").Body.String())
+	assert.Equal(t, "site 404", get(r, "/hook").Body.String())
+	assert.Equal(t, "api 404", get(r, "/api").Body.String())
+	assert.Equal(t, "api 404", get(r, "/api/other").Body.String())
+	assert.Equal(t, "api 404", get(r, "/api/users/5/extra").Body.String())
+	assert.Equal(t, "user", get(r, "/api/users/5").Body.String())
+}
@@ -0,0 +1,62 @@
+package route
+
+import (
+	"net/http"
+	"time"
+)
+
+// Observer is told of routing events by the router serving requests,
+// if set as its Observer field, so monitoring systems can follow
+// routing without middleware wrapping every handler:
+//
+//     type monitor struct{ route.NopObserver }
+//
+//     func (monitor) OnComplete(pattern string, status int, d time.Duration) {
+//         latency.WithLabelValues(pattern).Observe(d.Seconds())
+//     }
+//
+//     r.Observer = monitor{}
+//
+// Methods are called concurrently, on the goroutines serving requests,
+// so they should be quick.
+type Observer interface {
+	// OnMatch is called when a request matches the route with the
+	// given pattern, before its handler runs, with the values
+	// captured from the path, which must not be retained.
+	OnMatch(pattern string, vars map[string]string)
+	// OnNotFound is called when a request's path matches no route.
+	OnNotFound(path string)
+	// OnPanic is called with the value of a panic in a handler,
+	// before the panic continues up the stack.
+	OnPanic(err interface{})
+	// OnComplete is called when a request has been served, with the
+	// pattern of the route that matched it, if any, the response
+	// status, and the time taken.
+	OnComplete(pattern string, status int, d time.Duration)
+}
+
+// NopObserver is an Observer that does nothing, for embedding in
+// observers interested in only some events.
+type NopObserver struct{}
+
+func (NopObserver) OnMatch(pattern string, vars map[string]string)         {}
+func (NopObserver) OnNotFound(path string)                                 {}
+func (NopObserver) OnPanic(err interface{})                                {}
+func (NopObserver) OnComplete(pattern string, status int, d time.Duration) {}
+
+// serveObserved is ServeHTTP for a router with an Observer.
+func (r *Router) serveObserved(w http.ResponseWriter, req *http.Request, o Observer) {
+	start := timeNow()
+	sw := &statusWriter{ResponseWriter: w}
+	defer func() {
+		if v := recover(); v != nil {
+			o.OnPanic(v)
+			panic(v)
+		}
+	}()
+	pattern := r.serve(sw, req, o)
+	if sw.code == 0 {
+		sw.code = http.StatusOK
+	}
+	o.OnComplete(pattern, sw.code, timeNow().Sub(start))
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnMatch(pattern string, vars map[string]string) {
+	o.events = append(o.events, fmt.Sprintf("match %s %v", pattern, vars))
+}
+
+func (o *recordingObserver) OnNotFound(path string) {
+	o.events = append(o.events, "not found "+path)
+}
+
+func (o *recordingObserver) OnPanic(err interface{}) {
+	o.events = append(o.events, fmt.Sprintf("panic %v", err))
+}
+
+func (o *recordingObserver) OnComplete(pattern string, status int, d time.Duration) {
+	o.events = append(o.events, fmt.Sprintf("complete %q %d %v", pattern, status, d))
+}
+
+func TestObserver(t *testing.T) {
+	now := fakeClock(t)
+	o := &recordingObserver{}
+	r := &Router{Observer: o}
+	r.Route("/users/:id").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		*now = now.Add(time.Second)
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.Route("/boom").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		panic("boom")
+	})
+
+	get(r, "/users/5")
+	get(r, "/nowhere")
+	assert.PanicsWithValue(t, "boom", func() { get(r, "/boom") })
+	assert.Equal(t, []string{
+		"match /users/:id map[id:5]",
+		`complete "/users/:id" 201 1s`,
+		"not found /nowhere",
+		`complete "" 404 0s`,
+		"match /boom map[]",
+		"panic boom",
+	}, o.events)
+}
@@ -0,0 +1,153 @@
+// Package openapi generates OpenAPI 3 documents from a route.Router.
+//
+// Path patterns are converted to OpenAPI path templates, so that
+// "/users/:id" becomes "/users/{id}".  A trailing "*" fallback becomes
+// a "{path}" parameter holding the remaining path.
+//
+// Per-route documentation is read from the route's metadata, as in:
+//
+//     r.Route("/users/:id").
+//         SetMeta(openapi.Summary, "Show a user").
+//         SetMeta(openapi.Tags, []string{"users"}).
+//         FuncE(showUser)
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/evmar/route"
+)
+
+// Metadata keys read from routes; see route.Router.Meta.
+const (
+	Summary     = "summary"     // string
+	Description = "description" // string
+	Tags        = "tags"        // []string
+	Deprecated  = "deprecated"  // bool
+)
+
+// Document is an OpenAPI document.  Only the subset of the
+// specification that can be derived from a router is modeled.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info is the document's metadata.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*Operation
+
+// Operation describes a single route and method.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a path parameter.
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+// Schema is a minimal JSON schema.
+type Schema struct {
+	Type string `json:"type"`
+}
+
+// Response describes an operation's response.
+type Response struct {
+	Description string `json:"description"`
+}
+
+// Template converts a route pattern to an OpenAPI path template, and
+// returns the names of its parameters.
+func Template(pattern string) (string, []string) {
+	if pattern == "" {
+		return "/", nil
+	}
+	parts := strings.Split(pattern[1:], "/")
+	var params []string
+	for i, part := range parts {
+		if part == "*" {
+			part = ":path"
+		}
+		if len(part) > 0 && part[0] == ':' {
+			params = append(params, part[1:])
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return "/" + strings.Join(parts, "/"), params
+}
+
+// Generate builds an OpenAPI document describing every route in r.
+// Routes that accept any method are documented as GET, unless the
+// path also has a handler registered for GET with Method, which is
+// documented instead.
+func Generate(r *route.Router, info Info) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+	for _, ri := range r.Routes() {
+		path, params := Template(ri.Pattern)
+		item := doc.Paths[path]
+		if item == nil {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		methods := ri.Methods
+		if methods == nil {
+			if item["get"] != nil {
+				// A variant registered for GET describes it better.
+				continue
+			}
+			methods = []string{http.MethodGet}
+		}
+		for _, m := range methods {
+			op := &Operation{
+				OperationID: ri.Name,
+				Responses: map[string]*Response{
+					"default": {Description: "response"},
+				},
+			}
+			op.Summary, _ = ri.Meta[Summary].(string)
+			op.Description, _ = ri.Meta[Description].(string)
+			op.Tags, _ = ri.Meta[Tags].([]string)
+			op.Deprecated, _ = ri.Meta[Deprecated].(bool)
+			for _, p := range params {
+				op.Parameters = append(op.Parameters, Parameter{
+					Name:     p,
+					In:       "path",
+					Required: true,
+					Schema:   Schema{Type: "string"},
+				})
+			}
+			item[strings.ToLower(m)] = op
+		}
+	}
+	return doc
+}
+
+// Write generates the document for r and writes it to w as JSON.
+func Write(w io.Writer, r *route.Router, info Info) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Generate(r, info))
+}
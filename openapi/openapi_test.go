@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func h(w http.ResponseWriter, r *http.Request, env map[string]string) {}
+
+func TestTemplate(t *testing.T) {
+	path, params := Template("/users/:id/posts/:post")
+	assert.Equal(t, "/users/{id}/posts/{post}", path)
+	assert.Equal(t, []string{"id", "post"}, params)
+
+	path, params = Template("/static/*")
+	assert.Equal(t, "/static/{path}", path)
+	assert.Equal(t, []string{"path"}, params)
+
+	path, params = Template("")
+	assert.Equal(t, "/", path)
+	assert.Nil(t, params)
+}
+
+func TestGenerate(t *testing.T) {
+	r := &route.Router{}
+	r.Route("/users/:id").
+		Name("user.show").
+		SetMeta(Summary, "Show a user").
+		SetMeta(Tags, []string{"users"}).
+		FuncE(h)
+	r.Route("/about").FuncE(h)
+
+	doc := Generate(r, Info{Title: "test", Version: "1"})
+	assert.Equal(t, 2, len(doc.Paths))
+
+	op := doc.Paths["/users/{id}"]["get"]
+	assert.NotNil(t, op)
+	assert.Equal(t, "user.show", op.OperationID)
+	assert.Equal(t, "Show a user", op.Summary)
+	assert.Equal(t, []string{"users"}, op.Tags)
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: Schema{Type: "string"}}}, op.Parameters)
+
+	assert.NotNil(t, doc.Paths["/about"]["get"])
+
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, r, Info{Title: "test", Version: "1"}))
+	var decoded map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "3.0.3", decoded["openapi"])
+}
+
+func TestGenerateMethods(t *testing.T) {
+	r := &route.Router{}
+	r.Route("/users/:id").Method("GET").FuncE(h)
+	r.Route("/users/:id").Method("DELETE").FuncE(h)
+
+	item := Generate(r, Info{}).Paths["/users/{id}"]
+	assert.Equal(t, 2, len(item))
+	assert.NotNil(t, item["get"])
+	assert.NotNil(t, item["delete"])
+}
+
+func TestGenerateAnyAndMethod(t *testing.T) {
+	r := &route.Router{}
+	r.Route("/users/:id").Name("user.any").FuncE(h)
+	r.Route("/users/:id").Method("GET").Name("user.show").FuncE(h)
+	r.Route("/users/:id").Method("POST").Name("user.update").FuncE(h)
+	// Visited after the GET variant, but still loses to it.
+	r.Route("/users/:id").Header("Accept", "text/csv").Name("user.csv").FuncE(h)
+	r.Route("/files/:path").Method("GET").Name("file.show").FuncE(h)
+	r.Route("/files/*").Name("file.any").FuncE(h)
+
+	doc := Generate(r, Info{})
+	item := doc.Paths["/users/{id}"]
+	assert.Equal(t, 2, len(item))
+	assert.Equal(t, "user.show", item["get"].OperationID)
+	assert.Equal(t, "user.update", item["post"].OperationID)
+	assert.Equal(t, "file.show", doc.Paths["/files/{path}"]["get"].OperationID)
+}
@@ -0,0 +1,110 @@
+// Package otelroute traces requests served by a route.Router with
+// OpenTelemetry.
+//
+// Its middleware starts a server span for each request, named by the
+// method and the matched route's pattern, like "GET /users/:id",
+// rather than the raw path, so traces group by endpoint:
+//
+//     r.Use(otelroute.Tracing{}.Middleware())
+//
+// The span continues any trace propagated in the request's headers,
+// and is recorded in the request's context, so spans started by
+// handlers are its children.
+package otelroute
+
+import (
+	"net/http"
+
+	"github.com/evmar/route"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing configures the tracing middleware.
+type Tracing struct {
+	// TracerProvider creates the tracer.  If nil, the global provider
+	// is used.
+	TracerProvider trace.TracerProvider
+
+	// Propagator reads trace context from request headers.  If nil,
+	// the global propagator is used.
+	Propagator propagation.TextMapPropagator
+
+	// Redact, if set, is called with each value captured from the
+	// path, which are recorded as "route.var.<name>" attributes.  It
+	// returns the value to record, or false to omit the attribute,
+	// keeping user ids, tokens and the like out of traces.
+	Redact func(name, value string) (string, bool)
+}
+
+// Middleware returns middleware tracing requests as configured by t.
+func (t Tracing) Middleware() route.Middleware {
+	tp := t.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	tracer := tp.Tracer("github.com/evmar/route/otelroute")
+	prop := t.Propagator
+	if prop == nil {
+		prop = otel.GetTextMapPropagator()
+	}
+	return func(next route.HandlerE) route.HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			ctx := prop.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			pattern := route.Pattern(req)
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("http.route", pattern),
+				attribute.String("url.path", req.URL.Path),
+			}
+			for name, val := range env {
+				if t.Redact != nil {
+					var ok bool
+					if val, ok = t.Redact(name, val); !ok {
+						continue
+					}
+				}
+				attrs = append(attrs, attribute.String("route.var."+name, val))
+			}
+			ctx, span := tracer.Start(ctx, req.Method+" "+pattern,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs...))
+			defer span.End()
+
+			sw := &statusWriter{ResponseWriter: w}
+			next(sw, req.WithContext(ctx), env)
+			if sw.code == 0 {
+				sw.code = http.StatusOK
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", sw.code))
+			if sw.code >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(sw.code))
+			}
+		}
+	}
+}
+
+// statusWriter records the status of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if sw.code == 0 {
+		sw.code = code
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	if sw.code == 0 {
+		sw.code = http.StatusOK
+	}
+	return sw.ResponseWriter.Write(p)
+}
+
+func (sw *statusWriter) Unwrap() http.ResponseWriter { return sw.ResponseWriter }
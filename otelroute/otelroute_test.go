@@ -0,0 +1,60 @@
+package otelroute
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	r := &route.Router{}
+	r.Use(Tracing{
+		TracerProvider: tp,
+		Propagator:     propagation.TraceContext{},
+		Redact: func(name, value string) (string, bool) {
+			if name == "token" {
+				return "", false
+			}
+			return value, true
+		},
+	}.Middleware())
+	var handlerSpan trace.SpanContext
+	r.Route("/users/:id/:token").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		handlerSpan = trace.SpanContextFromContext(req.Context())
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest("GET", "/users/5/secret", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := rec.Ended()
+	assert.Equal(t, 1, len(spans))
+	span := spans[0]
+	assert.Equal(t, "GET /users/:id/:token", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.Parent().TraceID().String())
+	assert.Equal(t, span.SpanContext(), handlerSpan)
+	assert.Equal(t, codes.Error, span.Status().Code)
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "/users/:id/:token", attrs["http.route"].AsString())
+	assert.Equal(t, "5", attrs["route.var.id"].AsString())
+	assert.Equal(t, int64(503), attrs["http.response.status_code"].AsInt64())
+	_, ok := attrs["route.var.token"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,104 @@
+package route
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// ParseRoutes registers the routes listed in src, a compact routes
+// file in the style of Rails, on r.  Each line names a method, a
+// pattern and a handler from handlers, optionally followed by "as"
+// and a route name:
+//
+//     # Users.
+//     GET    /users          users.index
+//     GET    /users/:id      users.show   as user.show
+//     POST   /users          users.create
+//     ANY    /static/*       static
+//
+// The method ANY leaves the route unrestricted by method.  Blank lines
+// and lines starting with "#" are ignored.
+//
+// Errors report the line they occur on.  As with Load, either every
+// route in src is registered or none are.
+func ParseRoutes(r *Router, src string, handlers map[string]HandlerE) error {
+	lines, err := parseRouteLines(src)
+	if err != nil {
+		return err
+	}
+	reg := &Registry{Handlers: handlers}
+	tmp := &Router{Strict: true, CaseInsensitive: r.CaseInsensitive}
+	for _, l := range lines {
+		if err := tmp.load(l.LoadRoute, reg); err != nil {
+			return fmt.Errorf("route: line %d: %s: %w", l.line, l.Path, err)
+		}
+	}
+	return r.Merge(tmp, "")
+}
+
+// ReadRoutes parses a routes file in the format read by ParseRoutes,
+// without registering anything, for tools that process route tables.
+func ReadRoutes(src string) ([]LoadRoute, error) {
+	lines, err := parseRouteLines(src)
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]LoadRoute, len(lines))
+	for i, l := range lines {
+		routes[i] = l.LoadRoute
+	}
+	return routes, nil
+}
+
+// routeLine is a route parsed from a routes file, with the line it was
+// found on.
+type routeLine struct {
+	line int
+	LoadRoute
+}
+
+// parseRouteLines parses the syntax of a routes file; see ParseRoutes.
+func parseRouteLines(src string) ([]routeLine, error) {
+	var lines []routeLine
+	sc := bufio.NewScanner(strings.NewReader(src))
+	for n := 1; sc.Scan(); n++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		f := strings.Fields(text)
+		if len(f) != 3 && !(len(f) == 5 && f[3] == "as") {
+			return nil, fmt.Errorf("route: line %d: want \"METHOD /path handler [as name]\", got %q", n, text)
+		}
+		l := routeLine{line: n, LoadRoute: LoadRoute{Path: f[1], Handler: f[2]}}
+		if !validMethod(f[0]) {
+			return nil, fmt.Errorf("route: line %d: bad method %q", n, f[0])
+		}
+		if f[0] != "ANY" {
+			l.Methods = []string{f[0]}
+		}
+		if !strings.HasPrefix(l.Path, "/") {
+			return nil, fmt.Errorf("route: line %d: pattern %q must start with /", n, l.Path)
+		}
+		if len(f) == 5 {
+			l.Name = f[4]
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+// validMethod reports whether m looks like an HTTP method: one or more
+// upper-case letters.
+func validMethod(m string) bool {
+	if m == "" {
+		return false
+	}
+	for _, c := range m {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
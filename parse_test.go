@@ -0,0 +1,73 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoutes(t *testing.T) {
+	r := &Router{}
+	err := ParseRoutes(r, `
+# Users.
+GET    /users/:id   users.show  as user.show
+POST   /users/:id   admin
+ANY    /admin/*     admin
+`, testRegistry.Handlers)
+	assert.Nil(t, err)
+	assert.Equal(t, "show 5", do(r, "GET", "/users/5").Body.String())
+	assert.Equal(t, "admin5", do(r, "POST", "/users/5").Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, do(r, "PUT", "/users/5").Code)
+	assert.Equal(t, "admin", do(r, "DELETE", "/admin/x").Body.String())
+	u, _ := r.URL("user.show", 7)
+	assert.Equal(t, "/users/7", u)
+}
+
+func TestParseRoutesNamedMethods(t *testing.T) {
+	r := &Router{}
+	err := ParseRoutes(r, `
+GET  /users/:id  users.show  as user.show
+POST /users/:id  admin       as user.update
+`, testRegistry.Handlers)
+	assert.Nil(t, err)
+	assert.Equal(t, "show 5", do(r, "GET", "/users/5").Body.String())
+	assert.Equal(t, "admin5", do(r, "POST", "/users/5").Body.String())
+	u, _ := r.URL("user.show", 7)
+	assert.Equal(t, "/users/7", u)
+	u, _ = r.URL("user.update", 8)
+	assert.Equal(t, "/users/8", u)
+
+	err = ParseRoutes(&Router{}, "GET /a admin as x\nPOST /b admin as x", testRegistry.Handlers)
+	assert.NotNil(t, err)
+}
+
+func TestParseRoutesErrors(t *testing.T) {
+	for src, msg := range map[string]string{
+		"GET /x":                        "route: line 1: want",
+		"\nget /x admin":                "route: line 2: bad method",
+		"GET x admin":                   "route: line 1: pattern \"x\" must start with /",
+		"GET /x admin\nGET /x//y admin": "route: line 2: /x//y:",
+		"GET /x missing":                "route: line 1: /x: unknown handler",
+		"GET /x admin as":               "route: line 1: want",
+	} {
+		r := &Router{}
+		err := ParseRoutes(r, src, testRegistry.Handlers)
+		if assert.NotNil(t, err, src) {
+			assert.Contains(t, err.Error(), msg, src)
+		}
+		assert.Nil(t, r.lookupPath("/x", nil), src)
+	}
+}
+
+func TestReadRoutes(t *testing.T) {
+	routes, err := ReadRoutes("GET /users/:id users.show as user.show\nANY /x admin\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []LoadRoute{
+		{Path: "/users/:id", Methods: []string{"GET"}, Handler: "users.show", Name: "user.show"},
+		{Path: "/x", Handler: "admin"},
+	}, routes)
+
+	_, err = ReadRoutes("GET /x")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,31 @@
+package route
+
+import "net/http"
+
+// Pattern returns the pattern of the route that matched req, like
+// "/users/:id/edit", or "" if no route has matched it.  Unlike the
+// request path it has low cardinality, so it is suitable for labelling
+// metrics, logs and traces:
+//
+//     func logRequests(next route.HandlerE) route.HandlerE {
+//         return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+//             start := time.Now()
+//             next(w, r, env)
+//             log.Printf("%s %s %v", r.Method, route.Pattern(r), time.Since(start))
+//         }
+//     }
+//
+// ServeHTTP records the pattern in req.Pattern, as http.ServeMux does,
+// before calling any middleware or handler.
+func Pattern(req *http.Request) string {
+	return req.Pattern
+}
+
+// displayPattern returns r's pattern as shown to users, where the root
+// is "/" rather than "".
+func (r *Router) displayPattern() string {
+	if r.pattern == "" {
+		return "/"
+	}
+	return r.pattern
+}
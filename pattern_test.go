@@ -0,0 +1,34 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePattern(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(Pattern(r)))
+}
+
+func TestPattern(t *testing.T) {
+	r := &Router{}
+	r.Route("/").Func(writePattern)
+	r.Route("/users/:id/edit").Func(writePattern)
+	r.Route("/static/*").Func(writePattern)
+	r.Route("/api/:v").Method("GET").Func(writePattern)
+
+	var seen string
+	r.Route("/users").Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+			seen = Pattern(r)
+			next(w, r, env)
+		}
+	})
+
+	assert.Equal(t, "/", get(r, "/").Body.String())
+	assert.Equal(t, "/users/:id/edit", get(r, "/users/5/edit").Body.String())
+	assert.Equal(t, "/users/:id/edit", seen)
+	assert.Equal(t, "/static/*", get(r, "/static/css/site.css").Body.String())
+	assert.Equal(t, "/api/:v", get(r, "/api/2").Body.String())
+}
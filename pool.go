@@ -0,0 +1,37 @@
+package route
+
+import (
+	"net/http"
+	"sync"
+)
+
+// envPool holds env maps for reuse across requests.
+var envPool = sync.Pool{
+	New: func() interface{} { return make(map[string]string, 4) },
+}
+
+func getEnv() map[string]string {
+	return envPool.Get().(map[string]string)
+}
+
+// putEnv clears env and returns it to the pool.
+func putEnv(env map[string]string) {
+	clear(env)
+	envPool.Put(env)
+}
+
+// KeepEnv wraps a handler that needs to retain its env beyond the
+// request, for example by handing it to a goroutine, passing it a
+// private copy rather than the pooled map.  See HandlerE.
+func KeepEnv(h HandlerE) HandlerE {
+	return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		var copied map[string]string
+		if env != nil {
+			copied = make(map[string]string, len(env))
+			for k, v := range env {
+				copied[k] = v
+			}
+		}
+		h(w, r, copied)
+	}
+}
@@ -0,0 +1,40 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvPooled(t *testing.T) {
+	r := &Router{}
+	var retained map[string]string
+	r.Route("/a/:id").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		retained = env
+	})
+	var kept map[string]string
+	r.Route("/b/:id").FuncE(KeepEnv(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		kept = env
+	}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/a/1", nil))
+	// The pooled map is cleared after the handler returns.
+	assert.Equal(t, 0, len(retained))
+
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/b/2", nil))
+	assert.Equal(t, map[string]string{"id": "2"}, kept)
+}
+
+func BenchmarkServeHTTPVar(b *testing.B) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(F1)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/users/5", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
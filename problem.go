@@ -0,0 +1,122 @@
+package route
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// Problem is an RFC 7807 problem details document.  A handler
+// registered with FuncErr can return a *Problem to control the
+// document sent; see ProblemJSON.
+type Problem struct {
+	// Type is a URI identifying the kind of problem; empty means
+	// "about:blank", a plain HTTP error.
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// RequestID is an extension member holding the ID of the request,
+	// if any (see RequestIDs).
+	RequestID string `json:"requestId,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// WriteProblem writes p to w as application/problem+json.
+func WriteProblem(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// ProblemJSON makes errors at or beneath the current point respond
+// with application/problem+json documents: 404s and 405s generated by
+// the router, errors returned by FuncErr handlers, and panics in
+// handlers, which are recovered and reported as 500s.  It suits API
+// subtrees:
+//
+//     var errs route.ErrorMapper
+//     errs.Map(sql.ErrNoRows, http.StatusNotFound)
+//     r.Route("/api").ProblemJSON(&errs)
+//
+// Statuses for returned errors come from m, which may be nil; a
+// returned *Problem is sent as is, with Instance defaulting to the
+// request path.  As with ErrorHandler, the detail
+// of an error is only included for HTTPErrors with 4xx statuses.
+//
+// It sets the NotFound, MethodNotAllowed and ErrorHandler handlers of
+// the current point, which can be overridden beneath it.
+func (r *Router) ProblemJSON(m *ErrorMapper) {
+	if m == nil {
+		m = &ErrorMapper{}
+	}
+	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		WriteProblem(w, newProblem(req, http.StatusNotFound, ""))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, req *http.Request, allowed []string) {
+		WriteProblem(w, newProblem(req, http.StatusMethodNotAllowed, ""))
+	})
+	r.ErrorHandler(func(w http.ResponseWriter, req *http.Request, err error) {
+		var p *Problem
+		if errors.As(err, &p) {
+			// Copy, since p may be shared.
+			q := *p
+			if q.Status == 0 {
+				q.Status = http.StatusInternalServerError
+			}
+			if q.Instance == "" {
+				q.Instance = req.URL.Path
+			}
+			if q.RequestID == "" {
+				q.RequestID = RequestID(req)
+			}
+			WriteProblem(w, &q)
+			return
+		}
+		status := m.Status(err)
+		detail := ""
+		var he *HTTPError
+		if status < 500 && errors.As(err, &he) {
+			detail = he.Error()
+		}
+		if status >= 500 {
+			logf(req, "%s", err)
+		}
+		WriteProblem(w, newProblem(req, status, detail))
+	})
+	n := r
+	r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			defer func() {
+				if v := recover(); v != nil {
+					if _, ok := v.(badEnv); ok || v == http.ErrAbortHandler {
+						panic(v)
+					}
+					n.handleError(w, req, fmt.Errorf("panic: %v\n%s", v, debug.Stack()))
+				}
+			}()
+			next(w, req, env)
+		}
+	})
+}
+
+// newProblem returns a Problem for a plain HTTP error with status.
+func newProblem(req *http.Request, status int, detail string) *Problem {
+	return &Problem{
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  req.URL.Path,
+		RequestID: RequestID(req),
+	}
+}
@@ -0,0 +1,60 @@
+package route
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readProblem(t *testing.T, body []byte) Problem {
+	var p Problem
+	assert.Nil(t, json.Unmarshal(body, &p))
+	return p
+}
+
+func TestProblemJSON(t *testing.T) {
+	r := &Router{}
+	api := r.Route("/api")
+	api.ProblemJSON(nil)
+	api.Route("users/:id").Method("GET").FuncErr(failWith(Errorf(http.StatusNotFound, "no user")))
+	api.Route("fail").FuncErr(failWith(errors.New("secret")))
+	api.Route("custom").FuncErr(failWith(&Problem{Type: "https://example.com/quota", Title: "Quota exceeded", Status: http.StatusTooManyRequests}))
+	api.Route("panic").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		panic("boom")
+	})
+	r.Route("/html").FuncE(F1)
+
+	w := get(r, "/api/users/5")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, Problem{Title: "Not Found", Status: 404, Detail: "no user", Instance: "/api/users/5"}, readProblem(t, w.Body.Bytes()))
+
+	w = do(r, "POST", "/api/users/5")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+	assert.Equal(t, "Method Not Allowed", readProblem(t, w.Body.Bytes()).Title)
+
+	w = get(r, "/api/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	w = get(r, "/api/fail")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, Problem{Title: "Internal Server Error", Status: 500, Instance: "/api/fail"}, readProblem(t, w.Body.Bytes()))
+
+	w = get(r, "/api/custom")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "https://example.com/quota", readProblem(t, w.Body.Bytes()).Type)
+
+	w = get(r, "/api/panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "Internal Server Error", readProblem(t, w.Body.Bytes()).Title)
+
+	// Outside the subtree, errors are plain.
+	w = get(r, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,179 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProxyOptions configures Proxy.
+type ProxyOptions struct {
+	// KeepPrefix forwards the request's full path.  Otherwise, on a
+	// fallback route, only the part matched by "*" is forwarded, so
+	// the route's prefix is replaced by the target's path.
+	KeepPrefix bool
+
+	// PreserveHost forwards the request's Host header, rather than
+	// the target's host.
+	PreserveHost bool
+
+	// XForwarded sets X-Forwarded-For, X-Forwarded-Host and
+	// X-Forwarded-Proto on the outgoing request.  Any such headers on
+	// the incoming request are always dropped.
+	XForwarded bool
+
+	// Header is added to the outgoing request's headers, replacing
+	// values of the same name, and DropHeaders are removed from them.
+	Header      http.Header
+	DropHeaders []string
+
+	// Timeout, if non-zero, limits each request to the backend,
+	// answering 504 Gateway Timeout if it is exceeded.
+	Timeout time.Duration
+
+	// Transport performs the requests to the backend.  If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// Balancer chooses the backend for each request among the targets
+	// of ProxyPool.  If nil, a new RoundRobin is used.
+	Balancer Balancer
+
+	// MaxFails is how many requests in a row may fail to reach a
+	// backend, by error or Timeout, before it is taken out of the pool
+	// for FailTimeout.  If zero, they are 3 and 10 seconds.
+	MaxFails    int
+	FailTimeout time.Duration
+}
+
+// Proxy registers a handler at the current point forwarding requests
+// to target, using httputil.ReverseProxy, so the router can act as a
+// lightweight API gateway:
+//
+//     backend, _ := url.Parse("http://10.0.0.5:8080/api")
+//     r.Route("/backend/*").Proxy(backend, route.ProxyOptions{Timeout: 5 * time.Second})
+//
+// forwards "/backend/users/1" to "http://10.0.0.5:8080/api/users/1".
+// At most one ProxyOptions may be given.  Backends that can't be
+// reached get a 502 Bad Gateway, and the failure is logged along with
+// the matched pattern (see Pattern).
+//
+// Proxy panics if a handler is already registered at the current
+// point.
+func (r *Router) Proxy(target *url.URL, opts ...ProxyOptions) {
+	r.proxy([]*url.URL{target}, opts, "route.Proxy("+target.String()+")")
+}
+
+// ProxyPool is like Proxy, but spreads requests across a pool of
+// backends, as chosen by the Balancer option:
+//
+//     r.Route("/api/*").ProxyPool(backends, route.ProxyOptions{Balancer: &route.LeastConnections{}})
+//
+// Health is checked passively: a backend failing MaxFails requests in
+// a row is skipped for FailTimeout, unless every backend is failing.
+func (r *Router) ProxyPool(targets []*url.URL, opts ...ProxyOptions) {
+	if len(targets) == 0 {
+		panic("route: ProxyPool needs at least one target")
+	}
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.String()
+	}
+	r.proxy(targets, opts, "route.ProxyPool("+strings.Join(names, ", ")+")")
+}
+
+// proxy is the implementation of Proxy and ProxyPool.
+func (r *Router) proxy(targets []*url.URL, opts []ProxyOptions, name string) {
+	if len(opts) > 1 {
+		panic("route: Proxy takes at most one ProxyOptions")
+	}
+	var o ProxyOptions
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+	defer r.lock()()
+	if err := r.setHandler(o.handler(targets), name); err != nil {
+		panic(err.Error())
+	}
+}
+
+// upstreamKey is the context key under which the proxy records the
+// Upstream chosen for a request.
+type upstreamKey struct{}
+
+// handler returns the handler forwarding to targets with options o.
+func (o ProxyOptions) handler(targets []*url.URL) HandlerE {
+	p := &upstreamPool{balancer: o.Balancer, maxFails: o.MaxFails, failTimeout: o.FailTimeout}
+	if p.balancer == nil {
+		p.balancer = &RoundRobin{}
+	}
+	if p.maxFails == 0 {
+		p.maxFails = 3
+	}
+	if p.failTimeout == 0 {
+		p.failTimeout = 10 * time.Second
+	}
+	for _, t := range targets {
+		p.upstreams = append(p.upstreams, &Upstream{URL: t})
+	}
+
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(pr.In.Context().Value(upstreamKey{}).(*Upstream).URL)
+			if o.PreserveHost {
+				pr.Out.Host = pr.In.Host
+			}
+			if o.XForwarded {
+				pr.SetXForwarded()
+			}
+			for k, vs := range o.Header {
+				pr.Out.Header.Del(k)
+				for _, v := range vs {
+					pr.Out.Header.Add(k, v)
+				}
+			}
+			for _, k := range o.DropHeaders {
+				pr.Out.Header.Del(k)
+			}
+		},
+		Transport: o.Transport,
+		ModifyResponse: func(resp *http.Response) error {
+			resp.Request.Context().Value(upstreamKey{}).(*Upstream).fails.Store(0)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			if !errors.Is(err, context.Canceled) {
+				p.fail(req.Context().Value(upstreamKey{}).(*Upstream))
+			}
+			proxyError(w, req, err)
+		},
+	}
+	return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		if rest, ok := env["*"]; ok && !o.KeepPrefix {
+			req = stripRequest(req, "/"+rest)
+		}
+		if o.Timeout > 0 {
+			ctx, cancel := context.WithTimeout(req.Context(), o.Timeout)
+			defer cancel()
+			req = req.WithContext(ctx)
+		}
+		u := p.pick(req)
+		u.active.Add(1)
+		defer u.active.Add(-1)
+		rp.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), upstreamKey{}, u)))
+	}
+}
+
+// proxyError responds to a failure to reach a backend.
+func proxyError(w http.ResponseWriter, req *http.Request, err error) {
+	logf(req, "proxy error: %v", err)
+	status := http.StatusBadGateway
+	if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+	http.Error(w, http.StatusText(status), status)
+}
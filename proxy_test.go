@@ -0,0 +1,61 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("X-Host", r.Host)
+		w.Header().Set("X-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+		w.Header().Set("X-Token", r.Header.Get("X-Token"))
+		w.Header().Set("X-Gateway", r.Header.Get("X-Gateway"))
+		w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer backend.Close()
+	target, _ := url.Parse(backend.URL + "/api")
+
+	r := &Router{}
+	r.Route("/backend/*").Proxy(target, ProxyOptions{
+		XForwarded:  true,
+		Header:      http.Header{"X-Gateway": {"route"}},
+		DropHeaders: []string{"X-Token"},
+		Timeout:     20 * time.Millisecond,
+	})
+	r.Route("/api/*").Proxy(target, ProxyOptions{KeepPrefix: true, PreserveHost: true})
+
+	req := httptest.NewRequest("GET", "/backend/users/1?x=y", nil)
+	req.Header.Set("X-Token", "secret")
+	req.Header.Set("X-Forwarded-For", "6.6.6.6")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/api/users/1?x=y", w.Body.String())
+	assert.Equal(t, "192.0.2.1", w.Header().Get("X-Forwarded-For"))
+	assert.Equal(t, "", w.Header().Get("X-Token"))
+	assert.Equal(t, "route", w.Header().Get("X-Gateway"))
+	assert.Equal(t, target.Host, w.Header().Get("X-Host"))
+
+	w = get(r, "/api/users")
+	assert.Equal(t, "/api/api/users", w.Body.String())
+	assert.Equal(t, "example.com", w.Header().Get("X-Host"))
+	assert.Equal(t, "", w.Header().Get("X-Forwarded-For"))
+
+	assert.Equal(t, http.StatusGatewayTimeout, get(r, "/backend/slow").Code)
+
+	down, _ := url.Parse("http://127.0.0.1:1")
+	r.Route("/down/*").Proxy(down)
+	assert.Equal(t, http.StatusBadGateway, get(r, "/down/x").Code)
+
+	assert.Panics(t, func() { r.Route("/down/*").Proxy(down) })
+}
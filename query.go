@@ -0,0 +1,99 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// QueryOption configures a query parameter declared with Query.
+type QueryOption func(*queryParam)
+
+// queryParam is a query parameter declared with Query.
+type queryParam struct {
+	name     string
+	required bool
+	isInt    bool
+	def      *string
+}
+
+var (
+	// Required rejects requests without the parameter.
+	Required QueryOption = func(q *queryParam) { q.required = true }
+
+	// Int rejects requests where the parameter isn't an integer.
+	Int QueryOption = func(q *queryParam) { q.isInt = true }
+)
+
+// Default gives the value of the parameter when a request doesn't
+// have it, formatted as by fmt.Sprint.
+func Default(v interface{}) QueryOption {
+	s := fmt.Sprint(v)
+	return func(q *queryParam) { q.def = &s }
+}
+
+// Query declares a query parameter expected by routes at or beneath
+// the current point, and returns the router to allow chaining:
+//
+//     r.Route("/search").Query("q", route.Required).Query("page", route.Int, route.Default(1)).FuncE(search)
+//
+// The parameter's value is added to the handler's env under name,
+// which must not clash with the route's variables, and so is
+// available to the Env accessors like the captures: here
+// route.Env(env).MustInt("page").  A request missing a Required
+// parameter, or with a malformed one, is answered by the route's
+// error handler (see ErrorHandler) with an HTTPError with status 400
+// Bad Request, without calling the handler.  A parameter that is
+// neither Required nor Default is simply absent from env when missing.
+//
+// Like Use, Query applies to handlers registered beneath the current
+// point as well.
+func (r *Router) Query(name string, opts ...QueryOption) *Router {
+	q := &queryParam{name: name}
+	for _, o := range opts {
+		o(q)
+	}
+	if q.def != nil {
+		if err := q.check(*q.def); err != nil {
+			panic(fmt.Sprintf("route: bad default for query parameter %s: %s", name, err))
+		}
+	}
+	n := r
+	return r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			vals, ok := req.URL.Query()[q.name]
+			var v string
+			switch {
+			case ok:
+				v = vals[0]
+			case q.def != nil:
+				v = *q.def
+			case q.required:
+				n.handleError(w, req, Errorf(http.StatusBadRequest, "missing query parameter %s", q.name))
+				return
+			default:
+				next(w, req, env)
+				return
+			}
+			if err := q.check(v); err != nil {
+				n.handleError(w, req, err)
+				return
+			}
+			if env == nil {
+				env = make(map[string]string, 1)
+			}
+			env[q.name] = v
+			next(w, req, env)
+		}
+	})
+}
+
+// check returns an error if v is not a valid value for q.
+func (q *queryParam) check(v string) error {
+	if q.isInt {
+		if _, err := strconv.Atoi(v); err != nil {
+			return Errorf(http.StatusBadRequest, "invalid query parameter %s %q", q.name, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	r := &Router{}
+	r.Route("/search").Query("q", Required).Query("page", Int, Default(1)).Query("sort").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		sort, ok := env["sort"]
+		fmt.Fprintf(w, "%s %d %q %v", env["q"], Env(env).MustInt("page"), sort, ok)
+	})
+
+	assert.Equal(t, `go 1 "" false`, get(r, "/search?q=go").Body.String())
+	assert.Equal(t, `go 3 "name" true`, get(r, "/search?q=go&page=3&sort=name").Body.String())
+
+	w := get(r, "/search")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "missing query parameter q\n", w.Body.String())
+
+	w = get(r, "/search?q=go&page=two")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid query parameter page \"two\"\n", w.Body.String())
+
+	assert.Panics(t, func() { r.Route("/x").Query("n", Int, Default("many")) })
+}
+
+func TestQueryWithCaptures(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Query("tab", Default("posts")).FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		fmt.Fprintf(w, "%s %s", env["id"], env["tab"])
+	})
+	assert.Equal(t, "1 posts", get(r, "/users/1").Body.String())
+	assert.Equal(t, "2 likes", get(r, "/users/2?tab=likes").Body.String())
+}
@@ -0,0 +1,101 @@
+package route
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// timeNow is time.Now, replaceable in tests.
+var timeNow = time.Now
+
+// RateLimit is token-bucket rate-limiting middleware.  Each client may
+// make Burst requests at once, refilled at Rate per second; requests
+// beyond that get a 429 Too Many Requests with a Retry-After header.
+// Attaching limits at different points lets them differ by subtree:
+//
+//     r.Route("/login").Use(route.RateLimit{Rate: 1, Burst: 5}.Middleware())
+//     r.Route("/api").Use(route.RateLimit{Rate: 100, Burst: 200}.Middleware())
+//
+// Each call to Middleware starts a separate set of buckets.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+
+	// Key returns the name of the bucket a request draws from.  If
+	// nil, requests are keyed by ClientIP.
+	Key func(req *http.Request) string
+
+	// PerRoute gives each route beneath the middleware its own
+	// buckets, rather than sharing them across the subtree.
+	PerRoute bool
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Middleware returns middleware applying l.  It panics unless Rate
+// and Burst are positive.
+func (l RateLimit) Middleware() Middleware {
+	if l.Rate <= 0 || l.Burst <= 0 {
+		panic("route: RateLimit needs a positive Rate and Burst")
+	}
+	var mu sync.Mutex
+	buckets := map[string]*bucket{}
+	lastSweep := timeNow()
+	// full is how long an idle bucket takes to refill, after which it
+	// is indistinguishable from a new one and can be dropped.
+	full := time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+
+	// take takes a token from the bucket for key, returning zero if
+	// successful or how long until a token is available.
+	take := func(key string) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		now := timeNow()
+		if now.Sub(lastSweep) > full && now.Sub(lastSweep) > time.Minute {
+			for k, b := range buckets {
+				if now.Sub(b.last) > full {
+					delete(buckets, k)
+				}
+			}
+			lastSweep = now
+		}
+		b := buckets[key]
+		if b == nil {
+			b = &bucket{tokens: float64(l.Burst), last: now}
+			buckets[key] = b
+		}
+		b.tokens = math.Min(float64(l.Burst), b.tokens+now.Sub(b.last).Seconds()*l.Rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			return 0
+		}
+		return time.Duration((1 - b.tokens) / l.Rate * float64(time.Second))
+	}
+
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			var key string
+			if l.Key != nil {
+				key = l.Key(req)
+			} else {
+				key = ClientIP(req).String()
+			}
+			if l.PerRoute {
+				key = Pattern(req) + " " + key
+			}
+			if wait := take(key); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next(w, req, env)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock replaces timeNow for the duration of a test.
+func fakeClock(t *testing.T) *time.Time {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+	return &now
+}
+
+func TestRateLimit(t *testing.T) {
+	now := fakeClock(t)
+	r := &Router{}
+	r.Route("/login").Use(RateLimit{Rate: 0.5, Burst: 2}.Middleware()).FuncE(F1)
+	r.Route("/static").FuncE(F1)
+
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/login", "1.1.1.1:1", ""))
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/login", "1.1.1.1:1", ""))
+	w := get(r, "/login") // httptest's RemoteAddr is 192.0.2.1.
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, http.StatusTooManyRequests, fromAddr(r, "/login", "1.1.1.1:1", ""))
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/static", "1.1.1.1:1", ""))
+
+	*now = now.Add(time.Second)
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/login", nil)
+	req.RemoteAddr = "1.1.1.1:1"
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, fromAddr(r, "/login", "1.1.1.1:1", ""))
+}
+
+func TestRateLimitPerRoute(t *testing.T) {
+	fakeClock(t)
+	r := &Router{}
+	api := r.Route("/api")
+	api.Use(RateLimit{Rate: 1, Burst: 1, PerRoute: true, Key: func(*http.Request) string { return "all" }}.Middleware())
+	api.Route("a").FuncE(F1)
+	api.Route("b/:id").FuncE(F1)
+
+	assert.Equal(t, http.StatusOK, get(r, "/api/a").Code)
+	assert.Equal(t, http.StatusOK, get(r, "/api/b/1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "/api/b/2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "/api/a").Code)
+}
@@ -0,0 +1,140 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// PathCleaning is the type of Router.UncleanPaths.  A path is unclean
+// if it differs from the result of path.Clean, except that trailing
+// slashes are significant and so preserved.
+type PathCleaning int
+
+const (
+	// KeepPath matches paths as they are, the default.
+	KeepPath PathCleaning = iota
+	// CleanPath matches the cleaned form of unclean paths.  The
+	// request itself is passed to the handler unchanged.
+	CleanPath
+	// RedirectCleanPath redirects unclean paths to their cleaned form.
+	RedirectCleanPath
+	// RejectUncleanPath responds to unclean paths with 400 Bad Request.
+	RejectUncleanPath
+)
+
+// cleanPath is path.Clean, but preserving a trailing slash.
+func cleanPath(p string) string {
+	clean := path.Clean(p)
+	if strings.HasSuffix(p, "/") && clean != "/" {
+		clean += "/"
+	}
+	return clean
+}
+
+// get returns the i'th captured value.
+func (p *params) get(i int) string {
+	if i < len(p.buf) {
+		return p.buf[i].val
+	}
+	return p.more[i-len(p.buf)].val
+}
+
+// canonical returns the path that matches p.node exactly as
+// registered, using the values captured along the way.
+func (p *params) canonical() string {
+	if p.node.pattern == "" {
+		return "/"
+	}
+	parts := strings.Split(p.node.pattern[1:], "/")
+	i := 0
+	for j, part := range parts {
+		if part == "*" || (len(part) > 0 && part[0] == ':') {
+			parts[j] = p.get(i)
+			i++
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// redirect redirects req to the given path, keeping its query string.
+// If code is zero, it uses the default documented on ServeHTTP.
+func redirect(w http.ResponseWriter, req *http.Request, path string, code int) {
+	u := url.URL{Path: path, RawQuery: req.URL.RawQuery}
+	if code == 0 {
+		code = http.StatusPermanentRedirect
+		if req.Method == "GET" || req.Method == "HEAD" {
+			code = http.StatusMovedPermanently
+		}
+	}
+	http.Redirect(w, req, u.String(), code)
+}
+
+// toggleSlash returns path with its trailing slash added or removed,
+// or "" for the root path.
+func toggleSlash(path string) string {
+	if path == "/" {
+		return ""
+	}
+	if strings.HasSuffix(path, "/") {
+		return path[:len(path)-1]
+	}
+	return path + "/"
+}
+
+// matches reports whether any route matches path.
+func (r *Router) matches(path string) bool {
+	var p params
+	defer r.rlock()()
+	return r.lookup(path, 1, &p) != nil
+}
+
+// Redirect registers a route at from, beneath the current point,
+// redirecting requests to to with the given status, so moved pages
+// don't need handlers of their own:
+//
+//     r.Redirect("/old-path", "/new-path", http.StatusMovedPermanently)
+//     r.Redirect("/u/:id", "/users/:id", http.StatusMovedPermanently)
+//
+// to may use the variables and "*" captured by from, and may be a full
+// URL on another site.  The query string is kept.  Unlike
+// RewriteRedirect, the redirect is a route in the tree, listed by
+// Dump and subject to middleware.  Redirect panics if code isn't a 3xx
+// status, if to uses a variable that from doesn't capture, or if a
+// handler is already registered at from.
+func (r *Router) Redirect(from, to string, code int) {
+	if code < 300 || code > 399 {
+		panic(fmt.Sprintf("route: Redirect %s: status %d is not a redirect", from, code))
+	}
+	parts := strings.Split(to, "/")
+	for _, part := range parts {
+		if (part == "*" || strings.HasPrefix(part, ":")) && !strings.Contains(from+"/", "/"+part+"/") {
+			panic("route: Redirect " + to + ": " + part + " is not captured by " + from)
+		}
+	}
+	n := r.Route(from)
+	h := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		u := make([]string, len(parts))
+		for i, part := range parts {
+			switch {
+			case part == "*":
+				u[i] = env["*"]
+			case strings.HasPrefix(part, ":"):
+				u[i] = url.PathEscape(env[part[1:]])
+			default:
+				u[i] = part
+			}
+		}
+		target := strings.Join(u, "/")
+		if req.URL.RawQuery != "" {
+			target += "?" + req.URL.RawQuery
+		}
+		http.Redirect(w, req, target, code)
+	}
+	defer n.lock()()
+	if err := n.setHandler(h, "route.Redirect("+to+")"); err != nil {
+		panic(err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	r := &Router{CaseInsensitive: true}
+	r.Route("/About/Team").Func(writeString("team"))
+	r.Route("/Users/:id").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		w.Write([]byte(env["id"]))
+	})
+
+	assert.Equal(t, "team", get(r, "/about/team").Body.String())
+	assert.Equal(t, "team", get(r, "/ABOUT/Team").Body.String())
+	assert.Equal(t, "MixedCase", get(r, "/USERS/MixedCase").Body.String())
+
+	r.Compile()
+	assert.Equal(t, "team", get(r, "/aBoUt/TEAM").Body.String())
+
+	// Off by default.
+	r2 := &Router{}
+	r2.Route("/About").Func(writeString("about"))
+	assert.Equal(t, http.StatusNotFound, get(r2, "/about").Code)
+}
+
+func TestCaseRedirect(t *testing.T) {
+	r := &Router{CaseInsensitive: true, CaseRedirect: true}
+	r.Route("/about/team").Func(writeString("team"))
+	r.Route("/users/:id/Files/*").Func(writeString("files"))
+
+	assert.Equal(t, "team", get(r, "/about/team").Body.String())
+
+	w := get(r, "/About/Team?x=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/about/team?x=1", w.Header().Get("Location"))
+
+	w = get(r, "/USERS/Bob/FILES/A/b%20c")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/users/Bob/files/A/b%20c", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/ABOUT/team", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+}
+
+func TestRedirectTrailingSlash(t *testing.T) {
+	r := &Router{RedirectTrailingSlash: true}
+	r.Route("/dir/").Func(writeString("dir"))
+	r.Route("/file").Func(writeString("file"))
+	r.Route("/").Func(writeString("root"))
+
+	w := get(r, "/dir?a=b")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/dir/?a=b", w.Header().Get("Location"))
+
+	w = get(r, "/file/")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/file", w.Header().Get("Location"))
+
+	assert.Equal(t, "dir", get(r, "/dir/").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/other").Code)
+
+	r.TrailingSlashStatus = http.StatusFound
+	assert.Equal(t, http.StatusFound, get(r, "/dir").Code)
+
+	r.RedirectTrailingSlash = false
+	assert.Equal(t, http.StatusNotFound, get(r, "/dir").Code)
+}
+
+func TestUncleanPaths(t *testing.T) {
+	assert.Equal(t, "/a/b/", cleanPath("/a//b/"))
+	assert.Equal(t, "/b", cleanPath("/a/../b"))
+	assert.Equal(t, "/", cleanPath("/./"))
+	assert.Equal(t, "/a/", cleanPath("/a/"))
+
+	r := &Router{}
+	r.Route("/a/b").Func(echoPath)
+	assert.Equal(t, http.StatusNotFound, get(r, "/a//b").Code)
+
+	r.UncleanPaths = CleanPath
+	assert.Equal(t, "/x/../a/b", get(r, "/x/../a/b").Body.String())
+
+	r.UncleanPaths = RedirectCleanPath
+	w := get(r, "/a/./b?q=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/a/b?q=1", w.Header().Get("Location"))
+
+	r.UncleanPaths = RejectUncleanPath
+	assert.Equal(t, http.StatusBadRequest, get(r, "/a//b").Code)
+	assert.Equal(t, "/a/b", get(r, "/a/b").Body.String())
+}
+
+func TestRedirectRoute(t *testing.T) {
+	r := &Router{}
+	r.Redirect("/old-path", "/new-path", http.StatusMovedPermanently)
+	r.Route("/u").Redirect("/:id", "/users/:id", http.StatusFound)
+	r.Redirect("/docs/*", "https://docs.example.com/v2/*", http.StatusMovedPermanently)
+
+	w := get(r, "/old-path?a=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/new-path?a=1", w.Header().Get("Location"))
+
+	w = get(r, "/u/ann%20b")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/users/ann%20b", w.Header().Get("Location"))
+
+	assert.Equal(t, "https://docs.example.com/v2/guide/intro", get(r, "/docs/guide/intro").Header().Get("Location"))
+	assert.Contains(t, r.DumpString(), "route.Redirect(/new-path)")
+
+	assert.Panics(t, func() { r.Redirect("/a/:id", "/b/:name", http.StatusFound) })
+	assert.Panics(t, func() { r.Redirect("/old-path", "/other", http.StatusFound) })
+	assert.Panics(t, func() { r.Redirect("/gone", "/other", http.StatusOK) })
+	assert.Panics(t, func() { r.Redirect("/gone", "/other", 0) })
+	assert.Nil(t, r.lookupPath("/gone", nil))
+}
@@ -0,0 +1,120 @@
+// Package reload serves a route.Router whose routes come from a
+// configuration file, and rebuilds them whenever the file changes.
+//
+// The file is YAML or JSON in the format read by route.Load, listing
+// routes by pattern and the names of the Go handler and middleware to
+// serve each with:
+//
+//     routes:
+//       - path: /users/:id
+//         methods: [GET]
+//         handler: users.show
+//       - path: /static/*
+//         handler: static
+//
+// Names are resolved through a registry supplied by the program.  The
+// new routes replace the old ones with route.Router.Swap, so requests
+// in flight are unaffected; if the file fails to load, the previous
+// routes stay in place.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evmar/route"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Reloader keeps a router's routes in sync with a configuration file.
+type Reloader struct {
+	// Router is the router whose routes are replaced on each load.
+	Router *route.Router
+	// Path is the path of the configuration file.
+	Path string
+	// Handlers and Middleware map the names used in the file to
+	// handlers and middleware.
+	Handlers   map[string]route.HandlerE
+	Middleware map[string]route.Middleware
+	// OnError, if set, is called with errors from reloads triggered by
+	// Watch.  The previous routes remain in place after an error.
+	OnError func(error)
+}
+
+// New creates a Reloader and performs the initial load.  middleware
+// may be nil if the file names none.
+//
+// Only the routes beneath r are replaced, by this load and later
+// ones; middleware, NotFound handlers and the like set on r itself
+// stay in place (see route.Router.Swap).
+func New(r *route.Router, path string, handlers map[string]route.HandlerE, middleware map[string]route.Middleware) (*Reloader, error) {
+	l := &Reloader{Router: r, Path: path, Handlers: handlers, Middleware: middleware}
+	if err := l.Load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Load reads the configuration file and, if it is valid, replaces the
+// router's routes with the ones it describes.
+func (l *Reloader) Load() error {
+	data, err := os.ReadFile(l.Path)
+	if err != nil {
+		return err
+	}
+	next := &route.Router{CaseInsensitive: l.Router.CaseInsensitive}
+	reg := &route.Registry{Handlers: l.Handlers, Middleware: l.Middleware}
+	if err := route.LoadRegistry(next, data, reg); err != nil {
+		return fmt.Errorf("%s: %w", l.Path, err)
+	}
+	return l.Router.Swap(next)
+}
+
+// Watch watches the configuration file and reloads it on every change
+// until ctx is done.  It watches the file's directory, so it also
+// notices files replaced by renaming, as editors and deployment tools
+// often do.
+func (l *Reloader) Watch(ctx context.Context) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	if err := w.Add(filepath.Dir(l.Path)); err != nil {
+		return err
+	}
+	name := filepath.Clean(l.Path)
+
+	// Changes often arrive as bursts of events; wait for them to
+	// settle before reloading.
+	const settle = 50 * time.Millisecond
+	timer := time.NewTimer(settle)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-w.Events:
+			if filepath.Clean(ev.Name) == name && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				timer.Reset(settle)
+			}
+		case err := <-w.Errors:
+			l.error(err)
+		case <-timer.C:
+			if err := l.Load(); err != nil {
+				l.error(err)
+			}
+		}
+	}
+}
+
+func (l *Reloader) error(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
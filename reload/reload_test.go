@@ -0,0 +1,123 @@
+package reload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeString(s string) route.HandlerE {
+	return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		w.Write([]byte(s + env["id"]))
+	}
+}
+
+func get(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+var handlers = map[string]route.HandlerE{
+	"show": writeString("show "),
+	"list": writeString("list"),
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	os.WriteFile(path, []byte(`{"routes": [
+		{"path": "/users/:id", "methods": ["GET"], "handler": "show", "name": "user"},
+		{"path": "/users", "handler": "list"}
+	]}`), 0644)
+
+	r := &route.Router{}
+	l, err := New(r, path, handlers, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "show 5", get(r, "GET", "/users/5").Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, get(r, "POST", "/users/5").Code)
+	assert.Equal(t, "list", get(r, "GET", "/users").Body.String())
+
+	// Bad configurations leave the old routes in place.
+	for _, bad := range []string{
+		`{"routes": [`,
+		`{"routes": [{"path": "/x", "handler": "missing"}]}`,
+		`{"routes": [{"path": "/x//y", "handler": "list"}]}`,
+		`{"routes": [{"path": "/x", "handler": "list"}, {"path": "/x", "handler": "show"}]}`,
+	} {
+		os.WriteFile(path, []byte(bad), 0644)
+		assert.NotNil(t, l.Load(), bad)
+		assert.Equal(t, "list", get(r, "GET", "/users").Body.String())
+	}
+}
+
+func TestLoadKeepsSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	os.WriteFile(path, []byte(`{"routes": [
+		{"path": "/users", "handler": "list", "middleware": ["tag"]}
+	]}`), 0644)
+
+	r := &route.Router{}
+	r.Use(func(next route.HandlerE) route.HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if req.Header.Get("Authorization") == "" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next(w, req, env)
+		}
+	})
+	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "custom 404", http.StatusNotFound)
+	})
+	tag := func(next route.HandlerE) route.HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			w.Header().Set("X-Tag", "1")
+			next(w, req, env)
+		}
+	}
+	l, err := New(r, path, handlers, map[string]route.Middleware{"tag": tag})
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusForbidden, get(r, "GET", "/users").Code)
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Header.Set("Authorization", "x")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "list", w.Body.String())
+		assert.Equal(t, "1", w.Header().Get("X-Tag"))
+		assert.Equal(t, "custom 404\n", get(r, "GET", "/nowhere").Body.String())
+		assert.Nil(t, l.Load())
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	os.WriteFile(path, []byte(`{"routes": [{"path": "/a", "handler": "list"}]}`), 0644)
+
+	r := &route.Router{}
+	l, err := New(r, path, handlers, nil)
+	assert.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.Watch(ctx)
+	time.Sleep(50 * time.Millisecond)
+
+	tmp := path + ".tmp"
+	os.WriteFile(tmp, []byte(`{"routes": [{"path": "/b", "handler": "list"}]}`), 0644)
+	os.Rename(tmp, path)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for get(r, "GET", "/b").Code != http.StatusOK && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, "list", get(r, "GET", "/b").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "GET", "/a").Code)
+}
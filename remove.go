@@ -0,0 +1,191 @@
+package route
+
+import (
+	"fmt"
+	"strings"
+)
+
+// findPath returns the existing node for path, without creating any
+// nodes, or nil.
+func (r *Router) findPath(path string) *Router {
+	path = strings.TrimPrefix(path, "/")
+	n := r
+	for _, part := range strings.Split(path, "/") {
+		switch {
+		case len(part) > 0 && part[0] == ':':
+			if n.varRouter == nil || n.varName != part[1:] {
+				return nil
+			}
+			n = n.varRouter
+		case part == "*":
+			n = n.fallbackRouter
+		default:
+			if n.CaseInsensitive {
+				part = strings.ToLower(part)
+			}
+			n = n.matchers[part]
+		}
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// Remove unregisters the handler for pattern, which must be written
+// as it was registered, e.g. "/users/:id", along with any name given
+// to it.  Variants of the route (see Header and Method) are kept; use
+// RemoveMethod to remove those.  Nodes left with nothing in them, no
+// handlers or settings like middleware, are pruned from the tree;
+// routers for them that callers still hold, as returned by Route, go
+// back into the tree if they are used again.
+//
+// Remove is safe to call while the router is serving requests, and
+// returns an error if there is no handler for pattern or the router is
+// frozen.
+func (r *Router) Remove(pattern string) error {
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if r.isFrozen() {
+		return ErrFrozen
+	}
+	n := r.findPath(pattern)
+	if n == nil || n.handler == nil {
+		return fmt.Errorf("route: no handler for %q", pattern)
+	}
+	n.invalidate()
+	n.handler, n.handlerName, n.name, n.bind = nil, "", "", nil
+	n.prune()
+	return nil
+}
+
+// RemoveMethod unregisters the handler for method on pattern, as
+// registered with Method.  See Remove.
+func (r *Router) RemoveMethod(pattern, method string) error {
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if r.isFrozen() {
+		return ErrFrozen
+	}
+	n := r.findPath(pattern)
+	if n != nil {
+		method = strings.ToUpper(method)
+		for _, v := range n.variants {
+			if v.method == method && v.handler != nil {
+				v.invalidate()
+				v.handler, v.handlerName, v.name, v.bind = nil, "", "", nil
+				v.prune()
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("route: no %s handler for %q", method, pattern)
+}
+
+// prune detaches r from its parent if it no longer serves any purpose,
+// and then does the same for the parent.  Callers may still hold
+// pruned nodes, as in
+//
+//     api := r.Route("/api")
+//
+// so reattach puts them back if they are used again.
+func (r *Router) prune() {
+	for n := r; n.parent != nil; n = n.parent {
+		if n.hasHandlers() || n.configured() {
+			return
+		}
+		p := n.parent
+		switch {
+		case n.match != nil:
+			for i, v := range p.variants {
+				if v == n {
+					p.variants = append(p.variants[:i:i], p.variants[i+1:]...)
+					break
+				}
+			}
+		case p.varRouter == n:
+			p.varRouter, p.varName = nil, ""
+		case p.fallbackRouter == n:
+			p.fallbackRouter = nil
+		default:
+			for k, m := range p.matchers {
+				if m == n {
+					delete(p.matchers, k)
+				}
+			}
+		}
+		p.sortedKeys = nil
+	}
+}
+
+// configured reports whether anything other than routes was set on r,
+// which pruning would lose.
+func (r *Router) configured() bool {
+	return len(r.middleware) > 0 || r.notFound != nil || r.methodNotAllowed != nil ||
+		r.errorHandler != nil || r.maxBody != 0 || r.templates != nil || r.assets != nil ||
+		len(r.meta) > 0 || r.name != ""
+}
+
+// reattach links r and its ancestors back into the tree, if prune
+// detached them.  It fails if the path was registered again in the
+// meantime, so r's place is taken, or if Swap replaced r's tree.
+func (r *Router) reattach() error {
+	for n := r; n != nil; n = n.parent {
+		if n.orphaned {
+			return fmt.Errorf("route: %s was replaced by Swap", r.displayPattern())
+		}
+	}
+	if r.isFrozen() {
+		return nil
+	}
+	for n := r; n.parent != nil; n = n.parent {
+		p := n.parent
+		if n.match != nil {
+			found := false
+			for _, v := range p.variants {
+				found = found || v == n
+			}
+			if !found {
+				p.invalidate()
+				p.variants = append(p.variants, n)
+			}
+			continue
+		}
+		key := strings.TrimPrefix(n.pattern[len(p.pattern):], "/")
+		var cur *Router
+		switch {
+		case key == "*":
+			cur = p.fallbackRouter
+		case strings.HasPrefix(key, ":"):
+			cur = p.varRouter
+		default:
+			cur = p.matchers[key]
+		}
+		if cur == n {
+			continue
+		}
+		if cur != nil {
+			return fmt.Errorf("route: %s was removed and registered again", n.displayPattern())
+		}
+		p.invalidate()
+		switch {
+		case key == "*":
+			p.fallbackRouter = n
+		case strings.HasPrefix(key, ":"):
+			p.varName, p.varRouter = key[1:], n
+		default:
+			if p.matchers == nil {
+				p.matchers = make(map[string]*Router)
+			}
+			p.matchers[key] = n
+			p.sortedKeys = nil
+		}
+	}
+	return nil
+}
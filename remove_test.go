@@ -0,0 +1,98 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemove(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id/edit").Name("edit").FuncE(F1)
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+
+	assert.Nil(t, r.Remove("/users/:id/edit"))
+	assert.Nil(t, r.lookupPath("/users/5/edit", map[string]string{}))
+	assert.NotNil(t, r.lookupPath("/users/5", map[string]string{}))
+	_, err := r.URL("edit", 5)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, r.Remove("/users/:id"))
+	assert.Nil(t, r.Remove("/static/*"))
+	// Everything was pruned.
+	assert.Equal(t, 0, len(r.matchers))
+	assert.Nil(t, r.Check())
+
+	assert.NotNil(t, r.Remove("/users/:id"))
+	assert.NotNil(t, r.Remove("/nope"))
+
+	// The pattern can be registered again.
+	r.Route("/users/:name").FuncE(F1)
+}
+
+func TestRemoveMethod(t *testing.T) {
+	r := &Router{}
+	r.Route("/x").Method("GET").Func(writeString("get"))
+	r.Route("/x").Method("POST").Func(writeString("post"))
+
+	assert.Nil(t, r.RemoveMethod("/x", "post"))
+	assert.Equal(t, http.StatusMethodNotAllowed, do(r, "POST", "/x").Code)
+	assert.Equal(t, "GET, HEAD", do(r, "POST", "/x").Header().Get("Allow"))
+	assert.NotNil(t, r.RemoveMethod("/x", "POST"))
+
+	assert.Nil(t, r.RemoveMethod("/x", "GET"))
+	assert.Equal(t, http.StatusNotFound, do(r, "GET", "/x").Code)
+	assert.Equal(t, 0, len(r.matchers))
+}
+
+func TestRemoveHeldNode(t *testing.T) {
+	r := &Router{}
+	api := r.Route("/api")
+	api.Route("/a").Func(writeString("a"))
+	assert.Nil(t, r.Remove("/api/a"))
+	assert.Equal(t, http.StatusNotFound, get(r, "/api/a").Code)
+
+	// api was pruned, but still works.
+	api.Route("/b").Func(writeString("b"))
+	assert.Equal(t, "b", get(r, "/api/b").Body.String())
+
+	// Configuration keeps a node in the tree.
+	admin := r.Route("/admin")
+	admin.MaxBodySize(10)
+	admin.Route("/x").Func(writeString("x"))
+	assert.Nil(t, r.Remove("/admin/x"))
+	assert.Equal(t, int64(10), r.matchers["admin"].maxBody)
+
+	// A removed node can't come back once its place is taken.
+	old := r.Route("/users/:id")
+	old.Func(writeString("old"))
+	assert.Nil(t, r.Remove("/users/:id"))
+	r.Route("/users/:name").Func(writeString("new"))
+	assert.Panics(t, func() { old.Func(writeString("old")) })
+	assert.NotNil(t, old.TryFunc(writeString("old")))
+	_, err := old.RouteE("/x")
+	assert.NotNil(t, err)
+	assert.Equal(t, "new", get(r, "/users/1").Body.String())
+}
+
+func TestSwapHeldNode(t *testing.T) {
+	r := &Router{}
+	old := r.Route("/api")
+	old.Route("/a").Func(writeString("a"))
+	next := &Router{}
+	next.Route("/api/b").Func(writeString("b"))
+	assert.Nil(t, r.Swap(next))
+
+	// Routers from the replaced tree, and other, stay out of the tree.
+	assert.Panics(t, func() { old.Route("/c").Func(writeString("c")) })
+	_, err := old.RouteE("/c")
+	assert.NotNil(t, err)
+	assert.NotNil(t, old.TryFunc(writeString("api")))
+	assert.Panics(t, func() { next.Route("/d") })
+	assert.Equal(t, http.StatusNotFound, get(r, "/api/c").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/d").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/api").Code)
+	assert.Equal(t, "b", get(r, "/api/b").Body.String())
+}
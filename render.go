@@ -0,0 +1,98 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// RenderFunc writes v to w in some media type; see RegisterRenderer.
+type RenderFunc func(w io.Writer, v interface{}) error
+
+// renderers are the renderers available to Render, in order of
+// preference.
+var renderers struct {
+	sync.RWMutex
+	types []string
+	funcs []RenderFunc
+}
+
+func init() {
+	RegisterRenderer("application/json", func(w io.Writer, v interface{}) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+	RegisterRenderer("application/xml", func(w io.Writer, v interface{}) error {
+		return xml.NewEncoder(w).Encode(v)
+	})
+	RegisterRenderer("text/plain", func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprintln(w, v)
+		return err
+	})
+}
+
+// RegisterRenderer makes Render able to produce mediaType with f,
+// replacing any function already registered for it.  New media types
+// are preferred less than those registered before them, when a client
+// accepts several equally; JSON, XML and plain text are registered to
+// begin with, in that order.  For example, to add MessagePack:
+//
+//     route.RegisterRenderer("application/msgpack", func(w io.Writer, v interface{}) error {
+//         return msgpack.NewEncoder(w).Encode(v)
+//     })
+//
+// RegisterRenderer is typically called during initialization.
+func RegisterRenderer(mediaType string, f RenderFunc) {
+	mediaType = strings.ToLower(mediaType)
+	renderers.Lock()
+	defer renderers.Unlock()
+	for i, t := range renderers.types {
+		if t == mediaType {
+			renderers.funcs[i] = f
+			return
+		}
+	}
+	renderers.types = append(renderers.types, mediaType)
+	renderers.funcs = append(renderers.funcs, f)
+}
+
+// Render writes v as the response with status, in the registered
+// media type the request's Accept header prefers (see
+// RegisterRenderer), so one handler serves each client the format it
+// asks for:
+//
+//     return route.Render(w, req, http.StatusOK, report)
+//
+// Text types are sent with charset utf-8.  v is encoded before
+// anything is written, so if encoding fails, or the client accepts
+// none of the registered types, Render writes nothing and returns an
+// error, in the latter case an HTTPError with status 406 Not
+// Acceptable, for a FuncErr handler to return.
+func Render(w http.ResponseWriter, req *http.Request, status int, v interface{}) error {
+	renderers.RLock()
+	i := negotiate(req.Header.Get("Accept"), renderers.types)
+	if i < 0 {
+		renderers.RUnlock()
+		return Errorf(http.StatusNotAcceptable, "no acceptable representation")
+	}
+	mediaType, f := renderers.types[i], renderers.funcs[i]
+	renderers.RUnlock()
+
+	var buf bytes.Buffer
+	if err := f(&buf, v); err != nil {
+		return err
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		mediaType += "; charset=utf-8"
+	}
+	h := w.Header()
+	h.Set("Content-Type", mediaType)
+	h.Add("Vary", "Accept")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+	return nil
+}
@@ -0,0 +1,65 @@
+package route
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type renderPoint struct {
+	X int `json:"x" xml:"x"`
+}
+
+func (p renderPoint) String() string { return fmt.Sprintf("(%d)", p.X) }
+
+func render(accept string, v interface{}) (*httptest.ResponseRecorder, error) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	return w, Render(w, req, http.StatusCreated, v)
+}
+
+func TestRender(t *testing.T) {
+	w, err := render("", renderPoint{1})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	assert.Equal(t, `{"x":1}`+"\n", w.Body.String())
+
+	w, err = render("application/xml, application/json;q=0.5", renderPoint{2})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, "<renderPoint><x>2</x></renderPoint>", w.Body.String())
+
+	w, err = render("text/*", renderPoint{3})
+	assert.Nil(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "(3)\n", w.Body.String())
+
+	w, err = render("image/png", renderPoint{4})
+	assert.Equal(t, http.StatusNotAcceptable, defaultMapper.Status(err))
+	assert.Equal(t, 0, w.Body.Len())
+
+	// Encoding failures write nothing.
+	w, err = render("application/json", func() {})
+	assert.NotNil(t, err)
+	assert.False(t, w.Flushed || w.Body.Len() > 0)
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	RegisterRenderer("application/x-test", func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprintf(w, "test:%v", v)
+		return err
+	})
+	w, err := render("application/x-test", renderPoint{5})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/x-test", w.Header().Get("Content-Type"))
+	assert.Equal(t, "test:(5)", w.Body.String())
+}
@@ -0,0 +1,98 @@
+package route
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// RequestIDs is middleware giving each request an ID, for correlating
+// logs and error reports across services:
+//
+//     r.Use(route.RequestIDs{}.Middleware())
+//
+// A request arriving with an ID in the header, as from a load balancer
+// or another service, keeps it; otherwise a random one is made.  The
+// ID is sent back in the response's header, is returned by RequestID,
+// and is included in the router's log messages and in problem
+// documents (see ProblemJSON).
+type RequestIDs struct {
+	// Header is the header carrying IDs; "X-Request-ID" by default.
+	Header string
+
+	// Generate makes new IDs; by default they are 16 random bytes in
+	// hex.
+	Generate func() string
+}
+
+// requestIDKey is the context key of a request's ID.
+type requestIDKey struct{}
+
+// Middleware returns middleware applying ids.
+func (ids RequestIDs) Middleware() Middleware {
+	header := ids.Header
+	if header == "" {
+		header = "X-Request-ID"
+	}
+	generate := ids.Generate
+	if generate == nil {
+		generate = func() string {
+			b := make([]byte, 16)
+			rand.Read(b)
+			return hex.EncodeToString(b)
+		}
+	}
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			id := req.Header.Get(header)
+			if !validRequestID(id) {
+				id = generate()
+			}
+			w.Header().Set(header, id)
+			req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id))
+			next(w, req, env)
+		}
+	}
+}
+
+// validRequestID reports whether id, from a client, is acceptable as
+// a request ID: short, and of printable ASCII, so it can't forge log
+// lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID returns the ID given to req by the RequestIDs middleware,
+// or "" if there is none.
+func RequestID(req *http.Request) string {
+	return contextRequestID(req.Context())
+}
+
+func contextRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// logf logs a message about req, prefixed with its method, path,
+// pattern and ID, where known.
+func logf(req *http.Request, format string, args ...interface{}) {
+	prefix := "route: " + req.Method + " " + req.URL.Path
+	if req.Pattern != "" {
+		prefix += " (" + req.Pattern + ")"
+	}
+	if id := RequestID(req); id != "" {
+		prefix += " [" + id + "]"
+	}
+	log.Print(prefix + ": " + fmt.Sprintf(format, args...))
+}
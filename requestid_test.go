@@ -0,0 +1,58 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDs(t *testing.T) {
+	r := &Router{}
+	r.Use(RequestIDs{Generate: func() string { return "gen-1" }}.Middleware())
+	r.Route("/id").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(RequestID(req)))
+	})
+	r.Route("/fail").FuncErr(func(w http.ResponseWriter, req *http.Request, env map[string]string) error {
+		return errors.New("db down")
+	})
+	r.Route("/api").ProblemJSON(nil)
+	r.Route("/api/missing").FuncErr(func(w http.ResponseWriter, req *http.Request, env map[string]string) error {
+		return Errorf(http.StatusNotFound, "no such thing")
+	})
+
+	w := get(r, "/id")
+	assert.Equal(t, "gen-1", w.Body.String())
+	assert.Equal(t, "gen-1", w.Header().Get("X-Request-ID"))
+
+	req := httptest.NewRequest("GET", "/id", nil)
+	req.Header.Set("X-Request-ID", "upstream-7")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "upstream-7", w.Body.String())
+
+	// IDs that could forge log lines are replaced.
+	req.Header.Set("X-Request-ID", "a\nb")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "gen-1", w.Body.String())
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	get(r, "/fail")
+	log.SetOutput(os.Stderr)
+	assert.True(t, strings.Contains(logs.String(), "route: GET /fail (/fail) [gen-1]: db down"), logs.String())
+
+	var p Problem
+	assert.Nil(t, json.Unmarshal(get(r, "/api/missing").Body.Bytes(), &p))
+	assert.Equal(t, "gen-1", p.RequestID)
+
+	assert.Equal(t, "", RequestID(httptest.NewRequest("GET", "/", nil)))
+}
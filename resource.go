@@ -0,0 +1,120 @@
+package route
+
+import (
+	"strings"
+)
+
+// ResourceFuncs holds the handlers for a REST resource; see
+// Router.Resource.  Nil handlers are skipped.
+type ResourceFuncs struct {
+	Index   HandlerE // GET    /articles
+	New     HandlerE // GET    /articles/new
+	Create  HandlerE // POST   /articles
+	Show    HandlerE // GET    /articles/:id
+	Edit    HandlerE // GET    /articles/:id/edit
+	Update  HandlerE // PUT    /articles/:id (and PATCH)
+	Destroy HandlerE // DELETE /articles/:id
+
+	// Param is the name of the variable holding the id in member
+	// routes.  It defaults to "id" for top-level resources; see
+	// Resource.Resource for nested ones.
+	Param string
+}
+
+// action returns the handler for the named entry of restActions.
+func (f *ResourceFuncs) action(name string) HandlerE {
+	switch name {
+	case "Index":
+		return f.Index
+	case "New":
+		return f.New
+	case "Create":
+		return f.Create
+	case "Show":
+		return f.Show
+	case "Edit":
+		return f.Edit
+	case "Update":
+		return f.Update
+	case "Delete":
+		return f.Destroy
+	}
+	return nil
+}
+
+// Resource is a REST resource registered with Router.Resource.
+type Resource struct {
+	// Collection is the router for the resource's collection, as in
+	// "/articles".  Further routes can be registered on it.
+	Collection *Router
+
+	param string
+}
+
+// Member returns the router for a single item of the resource, as in
+// "/articles/:id", on which further routes can be registered.  The
+// member route is only added to the tree once it is needed, so that a
+// resource without member routes leaves no handlerless ":id" node
+// behind.
+func (res *Resource) Member() *Router {
+	return res.Collection.Route(":" + res.param)
+}
+
+// Resource registers the standard REST routes for the resource name
+// beneath the current point, listed on ResourceFuncs:
+//
+//     articles := r.Resource("articles", route.ResourceFuncs{
+//         Index: listArticles,
+//         Show:  showArticle,
+//     })
+//
+// Like Route, it panics if a route conflicts with an existing one.
+func (r *Router) Resource(name string, funcs ResourceFuncs) *Resource {
+	if funcs.Param == "" {
+		funcs.Param = "id"
+	}
+	return r.resource(name, funcs)
+}
+
+// Resource registers a resource nested beneath res's members:
+//
+//     articles.Resource("comments", route.ResourceFuncs{Show: showComment})
+//
+// registers "/articles/:id/comments/:comment_id".  Since the parent's
+// id is already named "id", a nested resource's Param defaults to the
+// resource name made singular, by turning a trailing "ies" into "y" or
+// else dropping a trailing "s", with "_id" appended, as in
+// "category_id" for "categories".
+func (res *Resource) Resource(name string, funcs ResourceFuncs) *Resource {
+	if funcs.Param == "" {
+		funcs.Param = singular(name) + "_id"
+	}
+	return res.Member().resource(name, funcs)
+}
+
+// singular returns the singular of the plural resource name.
+func singular(name string) string {
+	if stem, ok := strings.CutSuffix(name, "ies"); ok {
+		return stem + "y"
+	}
+	return strings.TrimSuffix(name, "s")
+}
+
+func (r *Router) resource(name string, funcs ResourceFuncs) *Resource {
+	res := &Resource{Collection: r.Route(name), param: funcs.Param}
+	for _, a := range restActions {
+		h := funcs.action(a.name)
+		if h == nil {
+			continue
+		}
+		n := res.Collection
+		if a.member {
+			n = res.Member()
+		}
+		if a.suffix != "" {
+			n = n.Route(a.suffix)
+		}
+		n.Method(a.method).FuncE(h)
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResource(t *testing.T) {
+	r := &Router{}
+	articles := r.Resource("articles", ResourceFuncs{
+		Index:   writeEnv("index"),
+		New:     writeEnv("new"),
+		Show:    writeEnv("show "),
+		Edit:    writeEnv("edit "),
+		Update:  writeEnv("update "),
+		Destroy: writeEnv("destroy "),
+	})
+	articles.Member().Route("publish").Method("POST").FuncE(writeEnv("publish "))
+	articles.Resource("comments", ResourceFuncs{
+		Show: func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+			w.Write([]byte(env["id"] + "/" + env["comment_id"]))
+		},
+	})
+
+	assert.Equal(t, "index", do(r, "GET", "/articles").Body.String())
+	assert.Equal(t, "new", do(r, "GET", "/articles/new").Body.String())
+	assert.Equal(t, "show 5", do(r, "GET", "/articles/5").Body.String())
+	assert.Equal(t, "edit 5", do(r, "GET", "/articles/5/edit").Body.String())
+	assert.Equal(t, "update 5", do(r, "PUT", "/articles/5").Body.String())
+	assert.Equal(t, "update 5", do(r, "PATCH", "/articles/5").Body.String())
+	assert.Equal(t, "destroy 5", do(r, "DELETE", "/articles/5").Body.String())
+	assert.Equal(t, "publish 5", do(r, "POST", "/articles/5/publish").Body.String())
+	assert.Equal(t, "5/7", do(r, "GET", "/articles/5/comments/7").Body.String())
+
+	// Create was not given.
+	assert.Equal(t, http.StatusMethodNotAllowed, do(r, "POST", "/articles").Code)
+}
+
+func TestResourceParam(t *testing.T) {
+	r := &Router{}
+	r.Resource("users", ResourceFuncs{
+		Show: func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+			w.Write([]byte(env["login"]))
+		},
+		Param: "login",
+	})
+	assert.Equal(t, "evmar", do(r, "GET", "/users/evmar").Body.String())
+}
+
+func TestResourceNestedParam(t *testing.T) {
+	r := &Router{}
+	shops := r.Resource("shops", ResourceFuncs{})
+	shops.Resource("categories", ResourceFuncs{
+		Show: func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+			w.Write([]byte(env["id"] + "/" + env["category_id"]))
+		},
+	})
+	assert.Equal(t, "3/4", do(r, "GET", "/shops/3/categories/4").Body.String())
+}
+
+func TestResourceFreeze(t *testing.T) {
+	r := &Router{}
+	r.Resource("articles", ResourceFuncs{Index: writeEnv("index")})
+	h, err := r.Freeze()
+	assert.Nil(t, err)
+	assert.Equal(t, "index", get(h, "/articles").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(h, "/articles/5").Code)
+}
@@ -0,0 +1,157 @@
+package route
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// Name assigns a name to the current point in the tree, so that URLs
+// for it can be generated with URL.  It returns the router to allow
+// chaining, as in:
+//
+//     r.Route("/users/:id").Name("user.show").FuncE(showUser)
+//
+// Method variants can be named too, so that each method at a path
+// has a name of its own:
+//
+//     r.Route("/users/:id").Method("PUT").Name("user.update").FuncE(updateUser)
+//
+// Name panics if the point is already named, or if another point in
+// the tree has the same name.
+func (r *Router) Name(name string) *Router {
+	defer r.lock()()
+	r.mustNotBeFrozen()
+	if err := r.checkName(name); err != nil {
+		panic(err.Error())
+	}
+	r.name = name
+	return r
+}
+
+// checkName returns an error if r can't be given name, because it is
+// already named or name is taken elsewhere in its tree.  The caller
+// must hold the lock.
+func (r *Router) checkName(name string) error {
+	if r.name != "" {
+		return fmt.Errorf("route: %s is already named %q", r.pattern, r.name)
+	}
+	if n := r.root().find(name); n != nil {
+		return fmt.Errorf("route: name %q is already used by %s", name, n.pattern)
+	}
+	return nil
+}
+
+// find returns the node beneath r with the given name, or nil.
+func (r *Router) find(name string) *Router {
+	if r.name == name {
+		return r
+	}
+	for _, v := range r.variants {
+		if n := v.find(name); n != nil {
+			return n
+		}
+	}
+	for _, m := range r.matchers {
+		if n := m.find(name); n != nil {
+			return n
+		}
+	}
+	if r.varRouter != nil {
+		if n := r.varRouter.find(name); n != nil {
+			return n
+		}
+	}
+	if r.fallbackRouter != nil {
+		return r.fallbackRouter.find(name)
+	}
+	return nil
+}
+
+// URL builds the path for the route registered under name.  Each
+// variable in the route's pattern, and the fallback "*" if present,
+// consumes one of args in order.
+//
+// Variable values are path-escaped; the fallback value is inserted as
+// is, so it may contain slashes.  If the tree serves Locales, the path
+// is prefixed with the first locale; see LocaleURL.
+func (r *Router) URL(name string, args ...interface{}) (string, error) {
+	path, err := r.url(name, args)
+	if err != nil {
+		return "", err
+	}
+	if ls := r.root().locales.Load(); ls != nil {
+		return localePath((*ls)[0], path), nil
+	}
+	return path, nil
+}
+
+// url builds the path for the route registered under name, without
+// any locale.
+func (r *Router) url(name string, args []interface{}) (string, error) {
+	unlock := r.rlock()
+	n := r.find(name)
+	unlock()
+	if n == nil {
+		return "", fmt.Errorf("route: no route named %q", name)
+	}
+	if n.pattern == "" {
+		return "/", nil
+	}
+	parts := strings.Split(n.pattern[1:], "/")
+	for i, part := range parts {
+		if part == "*" || (len(part) > 0 && part[0] == ':') {
+			if len(args) == 0 {
+				return "", fmt.Errorf("route: too few arguments for %q (%s)", name, n.pattern)
+			}
+			val := fmt.Sprint(args[0])
+			args = args[1:]
+			if part == "*" {
+				parts[i] = val
+			} else {
+				parts[i] = url.PathEscape(val)
+			}
+		}
+	}
+	if len(args) > 0 {
+		return "", fmt.Errorf("route: too many arguments for %q (%s)", name, n.pattern)
+	}
+	return "/" + strings.Join(parts, "/"), nil
+}
+
+// FuncMap returns template functions for reverse routing, for use
+// with html/template's Template.Funcs:
+//
+// "url" builds a path for a named route, as in {{ url "user.show" .ID }};
+// see URL for details.
+//
+// "localeURL" builds a path for a named route in a given locale, as in
+// {{ localeURL "fr" "user.show" .ID }}; see LocaleURL.
+//
+// "route" returns the pattern registered under a name,
+// as in {{ route "user.show" }} => "/users/:id".
+//
+// "asset" returns the fingerprinted URL of a file served by Assets,
+// as in {{ asset "app.js" }}; see AssetURL.
+//
+// "csrfToken" returns the token of a request protected by CSRF, and
+// "csrfField" a hidden form input holding it, as in
+// {{ csrfField .Request }}; see CSRFToken.
+func (r *Router) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"url":       r.URL,
+		"localeURL": r.LocaleURL,
+		"asset":     r.AssetURL,
+		"csrfToken": CSRFToken,
+		"csrfField": csrfField,
+		"route": func(name string) (string, error) {
+			defer r.rlock()()
+			n := r.find(name)
+			if n == nil {
+				return "", fmt.Errorf("route: no route named %q", name)
+			}
+			return n.pattern, nil
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package route
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	r := &Router{}
+	r.Route("/").Name("home").FuncE(F1)
+	r.Route("/users/:id").Name("user.show").FuncE(F1)
+	r.Route("/users/:id/posts/:post").Name("post.show").FuncE(F1)
+	r.Route("/static/*").Name("static").FuncE(F1)
+
+	u, err := r.URL("home")
+	assert.Nil(t, err)
+	assert.Equal(t, "/", u)
+
+	u, err = r.URL("user.show", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "/users/5", u)
+
+	u, err = r.URL("post.show", "a b", "x/y")
+	assert.Nil(t, err)
+	assert.Equal(t, "/users/a%20b/posts/x%2Fy", u)
+
+	u, err = r.URL("static", "css/site.css")
+	assert.Nil(t, err)
+	assert.Equal(t, "/static/css/site.css", u)
+
+	_, err = r.URL("missing")
+	assert.NotNil(t, err)
+	_, err = r.URL("user.show")
+	assert.NotNil(t, err)
+	_, err = r.URL("user.show", 1, 2)
+	assert.NotNil(t, err)
+}
+
+func TestNameDuplicate(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Name("user.show").FuncE(F1)
+	assert.Panics(t, func() { r.Route("/people/:id").Name("user.show") })
+	assert.Panics(t, func() { r.Route("/users/:id").Name("user.edit") })
+	u, err := r.URL("user.show", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "/users/1", u)
+
+	other := &Router{}
+	other.Route("/people/:id").Name("user.show").FuncE(F1)
+	assert.NotNil(t, r.Merge(other, ""))
+	assert.Nil(t, r.lookupPath("/people/1", nil))
+	assert.NotNil(t, r.Merge(other, "/v2"))
+	assert.Nil(t, r.lookupPath("/v2/people/1", nil))
+}
+
+func TestFuncMap(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Name("user.show").FuncE(F1)
+
+	tmpl := template.Must(template.New("t").Funcs(r.FuncMap()).Parse(
+		`<a href="{{ url "user.show" .ID }}">{{ route "user.show" }}</a>`))
+	var buf bytes.Buffer
+	assert.Nil(t, tmpl.Execute(&buf, struct{ ID int }{7}))
+	assert.Equal(t, `<a href="/users/7">/users/:id</a>`, buf.String())
+
+	bad := template.Must(template.New("t").Funcs(r.FuncMap()).Parse(`{{ url "nope" }}`))
+	assert.NotNil(t, bad.Execute(&buf, nil))
+}
@@ -0,0 +1,104 @@
+package route
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rewriteRule is a rule added by Rewrite or RewriteRedirect.
+type rewriteRule struct {
+	from, to []string
+	code     int
+}
+
+// Rewrite adds a rule rewriting request paths matching from to to,
+// before they are matched against the tree, so legacy URLs can be
+// served by the routes that replaced them:
+//
+//     r.Rewrite("/old/:id", "/new/:id")
+//     r.Rewrite("/blog/*", "/posts/*")
+//
+// from is a pattern with variables and an optional trailing "*", as
+// for Route, and to may use its values.  The handler sees the
+// rewritten path.  Rules apply to requests served by r, matched
+// against their whole path, and are tried in the order added; only
+// the first matching rule applies, and the result is not rewritten
+// again.  Rewrite is safe to call while serving.  It panics if to uses
+// a variable that from doesn't capture.
+func (r *Router) Rewrite(from, to string) {
+	r.addRewrite(from, to, 0)
+}
+
+// RewriteRedirect is like Rewrite, but redirects clients to the
+// rewritten path, with the given status, such as 301 Moved
+// Permanently, rather than serving it.  The query string is kept.
+func (r *Router) RewriteRedirect(from, to string, code int) {
+	r.addRewrite(from, to, code)
+}
+
+func (r *Router) addRewrite(from, to string, code int) {
+	rw := &rewriteRule{
+		from: strings.Split(strings.TrimPrefix(from, "/"), "/"),
+		to:   strings.Split(strings.TrimPrefix(to, "/"), "/"),
+		code: code,
+	}
+	vars := map[string]bool{}
+	for i, part := range rw.from {
+		if part == "*" && i != len(rw.from)-1 {
+			panic(fmt.Sprintf("route: Rewrite %q: \"*\" must come last", from))
+		}
+		if part == "*" || strings.HasPrefix(part, ":") {
+			vars[part] = true
+		}
+	}
+	for _, part := range rw.to {
+		if (part == "*" || strings.HasPrefix(part, ":")) && !vars[part] {
+			panic(fmt.Sprintf("route: Rewrite %q: %s is not captured by %q", to, part, from))
+		}
+	}
+	defer r.lock()()
+	var rules []*rewriteRule
+	if old := r.rewrites.Load(); old != nil {
+		rules = append(rules, *old...)
+	}
+	rules = append(rules, rw)
+	r.rewrites.Store(&rules)
+}
+
+// apply returns path rewritten by rw, if it matches.
+func (rw *rewriteRule) apply(path string) (string, bool) {
+	parts := strings.Split(path[1:], "/")
+	if len(parts) < len(rw.from) {
+		return "", false
+	}
+	for i, f := range rw.from {
+		if f == "*" {
+			break
+		}
+		if f != parts[i] && !strings.HasPrefix(f, ":") {
+			return "", false
+		}
+	}
+	if len(parts) > len(rw.from) && rw.from[len(rw.from)-1] != "*" {
+		return "", false
+	}
+	var b strings.Builder
+	for _, t := range rw.to {
+		b.WriteByte('/')
+		if t == "*" || strings.HasPrefix(t, ":") {
+			for i, f := range rw.from {
+				if f == t {
+					if f == "*" {
+						b.WriteString(strings.Join(parts[i:], "/"))
+					} else {
+						b.WriteString(parts[i])
+					}
+					break
+				}
+			}
+		} else {
+			b.WriteString(t)
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,35 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewrite(t *testing.T) {
+	r := &Router{}
+	r.Route("/new/:id").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		w.Write([]byte(req.URL.Path + " " + env["id"]))
+	})
+	r.Route("/posts/*").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		w.Write([]byte(env["*"]))
+	})
+	r.Rewrite("/old/:id", "/new/:id")
+	r.Rewrite("/old/:id/:rest", "/new/:rest")
+	r.Rewrite("/blog/*", "/posts/*")
+	r.RewriteRedirect("/legacy/:id", "/new/:id", http.StatusMovedPermanently)
+
+	assert.Equal(t, "/new/7 7", get(r, "/old/7").Body.String())
+	assert.Equal(t, "/new/b b", get(r, "/old/a/b").Body.String())
+	assert.Equal(t, "2024/hello", get(r, "/blog/2024/hello").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/old").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/old/a/b/c").Code)
+
+	w := get(r, "/legacy/9?x=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/new/9?x=1", w.Header().Get("Location"))
+
+	assert.Panics(t, func() { r.Rewrite("/a/:id", "/b/:name") })
+	assert.Panics(t, func() { r.Rewrite("/a/*/b", "/b") })
+}
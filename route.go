@@ -26,16 +26,79 @@
 package route
 
 import (
+	"bytes"
 	"fmt"
+	"html/template"
+	"io"
 	"log"
 	"net/http"
+	"net/netip"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-type handler func(w http.ResponseWriter, r *http.Request, env map[string]string)
+// HandlerE is an "extended" handler, which takes an additional
+// environment parameter holding the values captured from the path.
+// See FuncE.
+//
+// The env map is reused for later requests once the handler returns,
+// so handlers must not retain it, e.g. in a goroutine that outlives the
+// request; copy the values needed, or wrap the handler with KeepEnv.
+type HandlerE func(w http.ResponseWriter, r *http.Request, env map[string]string)
 
 // Router represents a single node in the matching tree.
 type Router struct {
+	// Strict makes registration reject malformed paths that are
+	// otherwise accepted: empty components other than a trailing
+	// slash, like "/foo//bar"; empty variable names; and "*" anywhere
+	// but the last component.  It is inherited by nodes created
+	// beneath this one, so set it before registering routes.
+	Strict bool
+
+	// CaseInsensitive makes static path components match regardless
+	// of case, by lowercasing them at registration and lookup.  Values
+	// captured by variables keep their original case.  Like Strict, it
+	// is inherited by nodes created beneath this one.
+	CaseInsensitive bool
+
+	// CaseRedirect, when set on the router serving requests along with
+	// CaseInsensitive, redirects requests whose static components
+	// differ in case from the registered route to the canonical URL,
+	// rather than serving them directly.  See ServeHTTP.
+	CaseRedirect bool
+
+	// RedirectTrailingSlash, when set on the router serving requests,
+	// redirects requests that match no route to the same path with
+	// the trailing slash added or removed, if that does match a route.
+	// TrailingSlashStatus is the redirect's status code; if zero,
+	// ServeHTTP's default is used.
+	RedirectTrailingSlash bool
+	TrailingSlashStatus   int
+
+	// UncleanPaths selects how the router serving requests treats
+	// paths containing elements like "//", "." or "..".
+	UncleanPaths PathCleaning
+
+	// TrustedProxies, when set on the router serving requests, lists
+	// the address ranges of reverse proxies whose forwarding headers
+	// are believed by ClientIP.
+	TrustedProxies []netip.Prefix
+
+	// Observer, when set on the router serving requests, is told of
+	// routing events; see Observer.
+	Observer Observer
+
+	// TrackStats, when set on the router serving requests, counts the
+	// requests served by each route; see Stats.
+	TrackStats bool
+
+	// SigningKey, on the root, is the HMAC key of the URLs made by
+	// SignedURL.  It must be kept secret.
+	SigningKey []byte
+
 	// matchers contains the subentries under this path.
 	matchers map[string]*Router
 
@@ -45,97 +108,444 @@ type Router struct {
 	varName   string
 	varRouter *Router
 
-	// handler is the handler for matches to this exact node.
-	handler handler
+	// handler is the handler for matches to this exact node, and
+	// handlerName is the name of the function registered for it.
+	handler     HandlerE
+	handlerName string
+
+	// bind, if set, made handler for this node; see setBoundHandler.
+	bind func(n *Router) HandlerE
+
+	// alias, if set, is the canonical node this one is an alias of;
+	// see Alias.
+	alias *Router
 
 	// fallback is the handler for falling back to if none of the above
 	// match; conceptually it's the "*" handler.
 	fallbackRouter *Router
+
+	// pattern is the full path pattern from the root of the tree to
+	// this node, e.g. "/users/:id".  The root itself has an empty pattern.
+	pattern string
+
+	// name is the name given to this node by Name, used for reverse routing.
+	name string
+
+	// meta holds arbitrary metadata attached to this node; see Meta.
+	meta map[string]interface{}
+
+	// middleware wraps the handlers of every route beneath this node;
+	// see Use.
+	middleware []Middleware
+
+	// variants are alternative handlers for this exact node that are
+	// chosen by other properties of the request, like headers.  For a
+	// variant itself, match tests whether a request is eligible and
+	// conds describes the conditions leading to it.  See Header.
+	variants []*Router
+	match    func(req *http.Request) bool
+	conds    []string
+
+	// accept is the media type produced by a variant created by Accept.
+	accept string
+
+	// vary is the request header read by a variant's condition, listed
+	// in the Vary header of responses from the path.
+	vary string
+
+	// mismatch is the HTTP status to fail with if a request matches
+	// no handler because it failed this variant's condition, or zero
+	// for the default of 404.
+	mismatch int
+
+	// parent is the node this one was created beneath, or nil for a root.
+	parent *Router
+
+	// skip and skipTo, when set by Compile, short-circuit a chain of
+	// nodes that each have a single static child and nothing else:
+	// a path starting with the components in skip continues matching
+	// at skipTo.
+	skip   []string
+	skipTo *Router
+
+	// frozen is set on the node Freeze was called on, and sortedKeys
+	// on every node beneath it.
+	frozen     atomic.Bool
+	sortedKeys []string
+
+	// mu guards the tree, when r is its root; see lock.
+	mu sync.RWMutex
+
+	// notFound handles requests beneath this node that match no route;
+	// see NotFound.
+	notFound http.HandlerFunc
+
+	// method is the HTTP method required by a variant created by
+	// Method, or by any variant beneath one.
+	method string
+
+	// methodNotAllowed handles requests beneath this node that match
+	// a route but not its methods; see MethodNotAllowed.
+	methodNotAllowed func(w http.ResponseWriter, r *http.Request, allowed []string)
+
+	// errorHandler handles errors returned by handlers beneath this
+	// node; see ErrorHandler.
+	errorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+	// maxBody limits the size of request bodies beneath this node, and
+	// bodyLimits is set on a root if any node has a limit; see
+	// MaxBodySize.
+	maxBody    int64
+	bodyLimits atomic.Bool
+
+	// assets holds the fingerprinted files served beneath this node;
+	// see Assets.
+	assets *assetSet
+
+	// templates renders HTML for handlers beneath this node; see
+	// Templates.
+	templates *template.Template
+
+	// hits counts the requests matching this node, and lastHit is the
+	// time of the latest, in Unix nanoseconds; see TrackStats.
+	hits    atomic.Int64
+	lastHit atomic.Int64
+
+	// debugLog, on a root, logs matching decisions; see SetDebugLog.
+	debugLog atomic.Pointer[func(format string, args ...interface{})]
+
+	// rewrites are the rules applied to paths served by this node
+	// before matching; see Rewrite.
+	rewrites atomic.Pointer[[]*rewriteRule]
+
+	// locales are the optional leading path segments of paths served
+	// by this node, and of paths built by URL on its tree; see Locales.
+	locales atomic.Pointer[[]string]
+
+	// cache, on a root, is the store used by Cache.
+	cache *MemoryCache
+
+	// orphaned is set on nodes Swap took out of the tree, which can't
+	// be changed any more; see reattach.
+	orphaned bool
 }
 
-func (r *Router) lookup(path []string, env map[string]string) handler {
+// params accumulates the values captured during lookup.  The first
+// few fit in a fixed-size buffer so that most lookups don't allocate.
+type params struct {
+	n   int
+	buf [8]struct{ key, val string }
+	// more holds captures beyond the capacity of buf.
+	more []struct{ key, val string }
+
+	// node is the node matched, and folded is set if matching it
+	// relied on CaseInsensitive.
+	node   *Router
+	folded bool
+}
+
+func (p *params) add(key, val string) {
+	if p.n < len(p.buf) {
+		p.buf[p.n].key, p.buf[p.n].val = key, val
+	} else {
+		p.more = append(p.more[:p.n-len(p.buf)], struct{ key, val string }{key, val})
+	}
+	p.n++
+}
+
+// fill copies the captured values into env.
+func (p *params) fill(env map[string]string) {
+	for i := 0; i < p.n; i++ {
+		if i < len(p.buf) {
+			env[p.buf[i].key] = p.buf[i].val
+		} else {
+			kv := p.more[i-len(p.buf)]
+			env[kv.key] = kv.val
+		}
+	}
+}
+
+// segment returns the path component starting at offset i of path,
+// and the offset of the following component.  An offset beyond the
+// end of path means there are no components left.
+func segment(path string, i int) (string, int) {
+	if j := strings.IndexByte(path[i:], '/'); j >= 0 {
+		return path[i : i+j], i + j + 1
+	}
+	return path[i:], len(path) + 1
+}
+
+// lookup finds the handler for the components of path starting at
+// offset i (see segment), recording captured values in p.
+func (r *Router) lookup(path string, i int, p *params) HandlerE {
 	// Empty path => we've matched on this router exactly.
-	if len(path) == 0 {
-		if r.handler != nil {
-			return r.handler
+	if i > len(path) {
+		if h := r.nodeHandler(); h != nil {
+			p.node = r
+			return r.wrap(h)
 		}
 		// TODO: maybe we should rely on fallback here too?
 		// E.g. with fallback on "/foo", is "/foo" itself a match?
 		return nil
 	}
 
+	if r.skipTo != nil {
+		// A compressed chain of static components; see Compile.
+		j := i
+		folded := false
+		for _, part := range r.skip {
+			if j > len(path) {
+				return nil
+			}
+			var comp string
+			comp, j = segment(path, j)
+			if comp != part {
+				if !r.CaseInsensitive || strings.ToLower(comp) != part {
+					return nil
+				}
+				folded = true
+			}
+		}
+		h := r.skipTo.lookup(path, j, p)
+		if h != nil && folded {
+			p.folded = true
+		}
+		return h
+	}
+
+	comp, next := segment(path, i)
 	if r.matchers != nil {
-		if r2 := r.matchers[path[0]]; r2 != nil {
-			if h := r2.lookup(path[1:], env); h != nil {
-				return h
+		key := comp
+		if r.CaseInsensitive {
+			key = strings.ToLower(comp)
+		}
+		if r2 := r.matchers[key]; r2 != nil {
+			if h := r2.lookup(path, next, p); h != nil {
+				if key != comp {
+					p.folded = true
+				}
+				return r.wrap(h)
 			}
 		}
 	}
-	if path[0] != "" && r.varRouter != nil {
-		env[r.varName] = path[0]
-		if h := r.varRouter.lookup(path[1:], env); h != nil {
-			return h
+	if comp != "" && r.varRouter != nil {
+		n := p.n
+		p.add(r.varName, comp)
+		if h := r.varRouter.lookup(path, next, p); h != nil {
+			return r.wrap(h)
 		}
+		p.n = n
 	}
 	if r.fallbackRouter != nil {
-		env["*"] = strings.Join(path, "/")
-		return r.fallbackRouter.handler
+		p.add("*", path[i:])
+		p.node = r.fallbackRouter
+		return r.wrap(r.fallbackRouter.wrap(r.fallbackRouter.nodeHandler()))
 	}
 	return nil
 }
 
-// lookupPath computes the handler matching a given request path string.
-// It just forwards to lookup.
-func (r *Router) lookupPath(path string, env map[string]string) handler {
-	if path[0] != '/' {
-		panic("bad path")
+// lookupPath computes the handler matching a given request path string,
+// storing any captured values in env.  Paths not starting with "/"
+// match nothing.
+func (r *Router) lookupPath(path string, env map[string]string) HandlerE {
+	if path == "" || path[0] != '/' {
+		return nil
 	}
-	parts := strings.Split(path[1:], "/")
-	return r.lookup(parts, env)
+	var p params
+	h := r.lookup(path, 1, &p)
+	if h != nil && env != nil {
+		p.fill(env)
+	}
+	return h
 }
 
 // ServeHTTP is the adapter for use in http.ListenAndServe.
+//
+// The env passed to handlers comes from a pool, so that serving
+// doesn't allocate; see HandlerE.
+//
+// Redirects issued by ServeHTTP (see CaseRedirect and
+// RedirectTrailingSlash) default to 301 Moved
+// Permanently for GET and HEAD requests and 308 Permanent Redirect,
+// which preserves the method and body, otherwise.
+//
+// Requests whose path doesn't start with "/", like "OPTIONS *", get a
+// 400 Bad Request; see Validate.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	env := map[string]string{}
-	if h := r.lookupPath(req.URL.Path, env); h != nil {
-		h(w, req, env)
+	if r.Observer != nil {
+		r.serveObserved(w, req, r.Observer)
 		return
 	}
-	http.NotFound(w, req)
+	r.serve(w, req, nil)
+}
+
+// serve is ServeHTTP, reporting to o if it is not nil.  It returns the
+// pattern of the route matched, if any.
+func (r *Router) serve(w http.ResponseWriter, req *http.Request, o Observer) string {
+	path := req.URL.Path
+	if path == "" || path[0] != '/' {
+		http.Error(w, "bad request path", http.StatusBadRequest)
+		return ""
+	}
+	if r.TrustedProxies != nil {
+		req = withTrustedProxies(req, r.TrustedProxies)
+	}
+	if r.UncleanPaths != KeepPath {
+		if clean := cleanPath(path); clean != path {
+			switch r.UncleanPaths {
+			case CleanPath:
+				path = clean
+			case RedirectCleanPath:
+				redirect(w, req, clean, 0)
+				return ""
+			case RejectUncleanPath:
+				http.Error(w, "bad request path", http.StatusBadRequest)
+				return ""
+			}
+		}
+	}
+	var locale, prefix string
+	if ls := r.locales.Load(); ls != nil {
+		if locale, path = splitLocale(*ls, path); locale != "" {
+			prefix = "/" + locale
+			req = stripRequest(req, path)
+		} else {
+			locale = negotiateLocale(req.Header.Get("Accept-Language"), *ls)
+		}
+	}
+	if rules := r.rewrites.Load(); rules != nil {
+		for _, rw := range *rules {
+			if to, ok := rw.apply(path); ok {
+				if rw.code != 0 {
+					redirect(w, req, prefix+to, rw.code)
+					return ""
+				}
+				path = to
+				req = stripRequest(req, to)
+				break
+			}
+		}
+	}
+	var p params
+	root := r.root()
+	frozen := root.frozen.Load()
+	if !frozen {
+		root.mu.RLock()
+	}
+	var h HandlerE
+	if logf := root.debugLog.Load(); logf != nil {
+		h = r.debugLookup(req, path, &p, *logf)
+	} else {
+		h = r.lookup(path, 1, &p)
+	}
+	if h != nil && p.node.alias != nil {
+		env := make(map[string]string, p.n)
+		p.fill(env)
+		path = p.node.aliasPath(env)
+		p = params{}
+		h = r.lookup(path, 1, &p)
+	}
+	var limit int64
+	if h != nil && root.bodyLimits.Load() {
+		limit = p.node.bodyLimit()
+	}
+	if !frozen {
+		root.mu.RUnlock()
+	}
+	if h != nil && p.folded && r.CaseRedirect {
+		redirect(w, req, prefix+p.canonical(), 0)
+		return ""
+	}
+	if h != nil {
+		req.Pattern = p.node.displayPattern()
+		if r.TrackStats {
+			p.node.hit()
+		}
+		if limit > 0 && !limitBody(w, req, p.node, limit) {
+			return req.Pattern
+		}
+		env := getEnv()
+		p.fill(env)
+		if locale != "" {
+			env["locale"] = locale
+		}
+		if o != nil {
+			o.OnMatch(req.Pattern, env)
+		}
+		call(h, p.node, w, req, env)
+		putEnv(env)
+		return req.Pattern
+	}
+	if r.RedirectTrailingSlash {
+		if alt := toggleSlash(path); alt != "" && r.matches(alt) {
+			redirect(w, req, prefix+alt, r.TrailingSlashStatus)
+			return ""
+		}
+	}
+	if o != nil {
+		o.OnNotFound(path)
+	}
+	r.notFoundAt(path)(w, req)
+	return ""
 }
 
-func (r *Router) route(parts []string) *Router {
+// child creates a new, unattached node for the path component part
+// beneath r.
+func (r *Router) child(part string) *Router {
+	r.invalidate()
+	return &Router{
+		pattern:         r.pattern + "/" + part,
+		Strict:          r.Strict,
+		CaseInsensitive: r.CaseInsensitive,
+		parent:          r,
+	}
+}
+
+// routeE is the implementation of RouteE, creating nodes as needed.
+func (r *Router) routeE(parts []string) (*Router, error) {
 	if len(parts) == 0 {
-		return r
+		return r, nil
 	}
 
 	part := parts[0]
+	if r.Strict && part == "" && len(parts) > 1 {
+		return nil, fmt.Errorf("empty path component in %q", r.pattern+"/"+strings.Join(parts, "/"))
+	}
 	if len(part) > 0 && part[0] == ':' {
 		part = part[1:]
+		if r.Strict && part == "" {
+			return nil, fmt.Errorf("empty variable name in %q", r.pattern+"/"+strings.Join(parts, "/"))
+		}
 		if r.varName != "" && part != r.varName {
-			log.Panicf("overlapping vars: %q / %q", r.varName, part)
+			return nil, fmt.Errorf("overlapping vars: %q / %q", r.varName, part)
 		}
 		if r.varRouter == nil {
 			r.varName = part
-			r.varRouter = &Router{}
+			r.varRouter = r.child(":" + part)
 		}
 		r = r.varRouter
 	} else if part == "*" {
 		if r.fallbackRouter != nil {
-			log.Panicf("overlapping fallback routes")
+			return nil, fmt.Errorf("overlapping fallback routes")
 		}
-		r.fallbackRouter = &Router{}
-		return r.fallbackRouter
+		if r.Strict && len(parts) > 1 {
+			return nil, fmt.Errorf("\"*\" must be the last component in %q", r.pattern+"/"+strings.Join(parts, "/"))
+		}
+		r.fallbackRouter = r.child("*")
+		return r.fallbackRouter, nil
 	} else {
+		if r.CaseInsensitive {
+			part = strings.ToLower(part)
+		}
 		if r.matchers == nil {
 			r.matchers = make(map[string]*Router)
 		}
 		if r.matchers[part] == nil {
-			r.matchers[part] = &Router{}
+			r.matchers[part] = r.child(part)
 		}
 		r = r.matchers[part]
 	}
-	return r.route(parts[1:])
+	return r.routeE(parts[1:])
 }
 
 // Route gets the router for a subpath off the current router.
@@ -148,51 +558,243 @@ func (r *Router) route(parts []string) *Router {
 // 2) the "*" component matches all paths, leaving it up to the
 // handler to further parse the path.  The matched subpath is also
 // captured in the environment (see the example).
+//
+// Route panics if the path conflicts with existing routes; see RouteE.
 func (r *Router) Route(path string) *Router {
+	r2, err := r.RouteE(path)
+	if err != nil {
+		log.Panic(err)
+	}
+	return r2
+}
+
+// RouteE is like Route, but returns an error rather than panicking if
+// the path conflicts with existing routes (or, in Strict mode, is
+// malformed).  It is intended for route tables built from user or
+// configuration input.
+func (r *Router) RouteE(path string) (*Router, error) {
+	unlock, err := r.lockE()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return r.routePath(path)
+}
+
+// routePath is RouteE without locking.
+func (r *Router) routePath(path string) (*Router, error) {
+	if r.isFrozen() {
+		return nil, ErrFrozen
+	}
+	if len(path) > 0 && path[0] == '/' {
+		path = path[1:]
+	}
+	parts := strings.Split(path, "/")
+	return r.routeE(parts)
+}
+
+// findRoute is like routePath, but changes nothing: it returns the
+// existing node for path, or nil if routePath would have to create it,
+// along with the error routePath would return.
+func (r *Router) findRoute(path string) (*Router, error) {
+	if r.isFrozen() {
+		return nil, ErrFrozen
+	}
 	if len(path) > 0 && path[0] == '/' {
 		path = path[1:]
 	}
 	parts := strings.Split(path, "/")
-	return r.route(parts)
+	for i, part := range parts {
+		var next *Router
+		switch {
+		case len(part) > 0 && part[0] == ':':
+			if part[1:] == r.varName {
+				next = r.varRouter
+			}
+		case part == "*":
+		default:
+			if r.CaseInsensitive {
+				part = strings.ToLower(part)
+			}
+			next = r.matchers[part]
+		}
+		if next == nil {
+			// Check the rest of the path on a scratch copy of r.
+			scratch := &Router{
+				pattern:         r.pattern,
+				Strict:          r.Strict,
+				CaseInsensitive: r.CaseInsensitive,
+				varName:         r.varName,
+				fallbackRouter:  r.fallbackRouter,
+			}
+			_, err := scratch.routeE(parts[i:])
+			return nil, err
+		}
+		r = next
+	}
+	return r, nil
 }
 
 // FuncE registers an "extended" handler, which takes an additional
 // environment parameter, at the current point.
 func (r *Router) FuncE(f func(w http.ResponseWriter, r *http.Request, env map[string]string)) {
-	if r.handler != nil {
-		panic("duplicate handler")
+	if err := r.TryFuncE(f); err != nil {
+		panic(err.Error())
 	}
-	r.handler = f
 }
 
 // Func registers an http.HandlerFunc at the current point.
 func (r *Router) Func(f func(http.ResponseWriter, *http.Request)) {
-	r.FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+	if err := r.TryFunc(f); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryFuncE is like FuncE, but returns an error rather than panicking
+// if a handler is already registered at the current point.
+func (r *Router) TryFuncE(f func(w http.ResponseWriter, r *http.Request, env map[string]string)) error {
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return r.setHandler(f, funcName(f))
+}
+
+// TryFunc is like Func, but returns an error rather than panicking
+// if a handler is already registered at the current point.
+func (r *Router) TryFunc(f func(http.ResponseWriter, *http.Request)) error {
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return r.setHandler(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		f(w, r)
+	}, funcName(f))
+}
+
+// ReplaceFuncE is like FuncE, but replaces any handler already
+// registered at the current point rather than panicking, for tests and
+// hot-swapping handlers on a live router.
+func (r *Router) ReplaceFuncE(f func(w http.ResponseWriter, r *http.Request, env map[string]string)) {
+	defer r.lock()()
+	r.replaceHandler(f, funcName(f))
+}
+
+// ReplaceFunc is like Func, but replaces any handler already registered
+// at the current point.  See ReplaceFuncE.
+func (r *Router) ReplaceFunc(f func(http.ResponseWriter, *http.Request)) {
+	defer r.lock()()
+	r.replaceHandler(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
 		f(w, r)
-	})
+	}, funcName(f))
+}
+
+// WrapHandler replaces the handler registered at the current point
+// with the result of calling wrap on it, as in:
+//
+//     r.Route("/slow").WrapHandler(func(old route.HandlerE) route.HandlerE {
+//         return withTimeout(old)
+//     })
+//
+// It returns an error if no handler is registered there.
+func (r *Router) WrapHandler(wrap func(old HandlerE) HandlerE) error {
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if r.handler == nil {
+		return fmt.Errorf("no handler to wrap")
+	}
+	bind := r.bind
+	r.replaceHandler(wrap(r.handler), r.handlerName)
+	if bind != nil {
+		r.bind = func(n *Router) HandlerE { return wrap(bind(n)) }
+	}
+	return nil
+}
+
+func (r *Router) replaceHandler(h HandlerE, name string) {
+	r.invalidate()
+	r.handler = h
+	r.handlerName = name
+	r.bind = nil
+}
+
+// setHandler attaches h to the current point.  name is the name of the
+// function the caller registered, which may differ from h's if h wraps it.
+func (r *Router) setHandler(h HandlerE, name string) error {
+	if r.isFrozen() {
+		return ErrFrozen
+	}
+	if r.handler != nil {
+		return fmt.Errorf("duplicate handler")
+	}
+	r.invalidate()
+	r.handler = h
+	r.handlerName = name
+	return nil
+}
+
+// setBoundHandler is like setHandler, for handlers that refer to the
+// node they are registered at, say to find its error handler.  bind
+// makes the handler for a node, so that Merge and Swap can make it
+// anew for the node they copy it to, rather than leave it referring
+// to the original.
+func (r *Router) setBoundHandler(bind func(n *Router) HandlerE, name string) error {
+	if err := r.setHandler(bind(r), name); err != nil {
+		return err
+	}
+	r.bind = bind
+	return nil
+}
+
+// funcName returns the name of the function f, for display.
+func funcName(f interface{}) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "?"
 }
 
-// Dump dumps the routing table to stdout.
+// Dump writes the routing table to w, in the order visited by Walk.
 // It can be useful for debugging.
-func (r *Router) Dump(prefix string) {
+func (r *Router) Dump(w io.Writer) {
+	defer r.rlock()()
+	r.dump(w, "")
+}
+
+// DumpString returns the output of Dump as a string.
+func (r *Router) DumpString() string {
+	var buf bytes.Buffer
+	r.Dump(&buf)
+	return buf.String()
+}
+
+func (r *Router) dump(w io.Writer, prefix string) {
 	if r.handler != nil {
-		fmt.Printf("%s=> %v\n", prefix, r.handler)
+		fmt.Fprintf(w, "%s=> %s\n", prefix, r.handlerName)
 	}
 
-	if r.matchers != nil {
-		for k, v := range r.matchers {
-			fmt.Printf("%s%s/\n", prefix, k)
-			v.Dump(prefix + "  ")
-		}
+	for _, v := range r.variants {
+		fmt.Fprintf(w, "%s[%s]\n", prefix, v.conds[len(v.conds)-1])
+		v.dump(w, prefix+"  ")
+	}
+
+	for _, k := range r.staticKeys() {
+		fmt.Fprintf(w, "%s%s/\n", prefix, k)
+		r.matchers[k].dump(w, prefix+"  ")
 	}
 
 	if r.varName != "" {
-		fmt.Printf("%s:%s\n", prefix, r.varName)
-		r.varRouter.Dump(prefix + "  ")
+		fmt.Fprintf(w, "%s:%s\n", prefix, r.varName)
+		r.varRouter.dump(w, prefix+"  ")
 	}
 
 	if r.fallbackRouter != nil {
-		fmt.Printf("%s*\n", prefix)
-		r.fallbackRouter.Dump(prefix + "  ")
+		fmt.Fprintf(w, "%s*\n", prefix)
+		r.fallbackRouter.dump(w, prefix+"  ")
 	}
 }
@@ -3,6 +3,7 @@ package route
 import (
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -153,3 +154,127 @@ func ExampleRouter_fallbacks() {
 	// Paths like "/static/foo/bar" will match staticHandler;
 	// env["*"] will be "foo/bar".
 }
+
+func TestDump(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/static/*").Func(http.NotFound)
+	r.Route("/about").FuncE(F1)
+	assert.Equal(t, `about/
+  => github.com/evmar/route.F1
+static/
+  *
+    => net/http.NotFound
+users/
+  :id
+    => github.com/evmar/route.F1
+`, r.DumpString())
+}
+
+func TestRouteE(t *testing.T) {
+	r := &Router{}
+	_, err := r.RouteE("/foo/:id")
+	assert.Nil(t, err)
+	_, err = r.RouteE("/foo/:name")
+	assert.NotNil(t, err)
+
+	_, err = r.RouteE("/bar/*")
+	assert.Nil(t, err)
+	_, err = r.RouteE("/bar/*")
+	assert.NotNil(t, err)
+
+	// Lax by default.
+	_, err = r.RouteE("/baz//x/*/y")
+	assert.Nil(t, err)
+
+	assert.Panics(t, func() { r.Route("/foo/:other") })
+}
+
+func TestStrict(t *testing.T) {
+	r := &Router{Strict: true}
+	for _, path := range []string{"/a//b", "/a/:", "/a/*/b"} {
+		_, err := r.RouteE(path)
+		assert.NotNil(t, err, path)
+	}
+	for _, path := range []string{"/", "/a/", "/a/:id/", "/a/*"} {
+		_, err := r.RouteE(path)
+		assert.Nil(t, err, path)
+	}
+
+	// Inherited by subrouters.
+	_, err := r.Route("/sub").RouteE("x//y")
+	assert.NotNil(t, err)
+}
+
+func TestTryFunc(t *testing.T) {
+	r := &Router{}
+	assert.Nil(t, r.Route("/foo").TryFuncE(F1))
+	assert.NotNil(t, r.Route("/foo").TryFuncE(F1))
+	assert.NotNil(t, r.Route("/foo").TryFunc(http.NotFound))
+	assert.Panics(t, func() { r.Route("/foo").FuncE(F1) })
+}
+
+func TestManyVars(t *testing.T) {
+	r := &Router{}
+	pattern := ""
+	path := ""
+	for i := 0; i < 12; i++ {
+		pattern += "/:v" + string(rune('a'+i))
+		path += "/" + string(rune('a'+i))
+	}
+	r.Route(pattern).FuncE(F1)
+	r.Route(pattern + "/x/:last").FuncE(F1)
+
+	env := map[string]string{}
+	assert.NotNil(t, r.lookupPath(path, env))
+	assert.Equal(t, 12, len(env))
+	assert.Equal(t, "l", env["vl"])
+
+	env = map[string]string{}
+	assert.NotNil(t, r.lookupPath(path+"/x/y", env))
+	assert.Equal(t, 13, len(env))
+	assert.Equal(t, "y", env["last"])
+}
+
+func TestServeHTTPAllocs(t *testing.T) {
+	r := &Router{}
+	r.Route("/api/users/list").FuncE(F1)
+	r.Route("/api/users/:id").FuncE(F1)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/users/list", nil)
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { r.ServeHTTP(w, req) }))
+
+	var id string
+	r.Route("/env/:id").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		id = env["id"]
+	})
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/env/5", nil))
+	assert.Equal(t, "5", id)
+
+	// Routes capturing nothing still get a map handlers can write to.
+	r.Route("/write").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		env["x"] = "1"
+		id = env["x"]
+	})
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/write", nil))
+	assert.Equal(t, "1", id)
+}
+
+func TestReplaceFunc(t *testing.T) {
+	r := &Router{}
+	r.Route("/a").Func(writeString("one"))
+	r.Route("/a").ReplaceFunc(writeString("two"))
+	assert.Equal(t, "two", get(r, "/a").Body.String())
+
+	r.Route("/b").ReplaceFuncE(F1)
+	assert.NotNil(t, r.lookupPath("/b", nil))
+
+	assert.Nil(t, r.Route("/a").WrapHandler(func(old HandlerE) HandlerE {
+		return func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+			w.Write([]byte("wrapped "))
+			old(w, r, env)
+		}
+	}))
+	assert.Equal(t, "wrapped two", get(r, "/a").Body.String())
+	assert.NotNil(t, r.Route("/c").WrapHandler(func(old HandlerE) HandlerE { return old }))
+}
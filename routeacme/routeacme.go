@@ -0,0 +1,73 @@
+// Package routeacme serves a route.Router over HTTPS with certificates
+// obtained automatically from an ACME certificate authority, such as
+// Let's Encrypt, using golang.org/x/crypto/acme/autocert:
+//
+//     m := &autocert.Manager{
+//         Prompt:     autocert.AcceptTOS,
+//         HostPolicy: autocert.HostWhitelist("example.com"),
+//         Cache:      autocert.DirCache("/var/cache/app/certs"),
+//     }
+//     log.Fatal(routeacme.Serve(ctx, r, m))
+//
+// It is a separate package so that programs using only the router
+// don't depend on golang.org/x/crypto.
+package routeacme
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/evmar/route"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ChallengePath is the pattern at which Register serves HTTP-01
+// challenges.
+const ChallengePath = "/.well-known/acme-challenge/:token"
+
+// Register registers m's answers to HTTP-01 challenges at
+// ChallengePath in r, and returns the router for it.  The route shows
+// up in the tree like any other, so it is not shadowed by a catch-all
+// route and middleware such as logging applies to it.
+func Register(r *route.Router, m *autocert.Manager) *route.Router {
+	n := r.Route(ChallengePath)
+	n.Method(http.MethodGet).Func(m.HTTPHandler(nil).ServeHTTP)
+	return n
+}
+
+// Serve registers m's challenges in r, as by Register, and serves r
+// until ctx is done, as by Router.ServeListeners: over HTTPS on port
+// 443, with certificates from m, and over HTTP on port 80, where
+// challenges are answered and every other request is redirected to
+// HTTPS.  At most one ServeOptions may be given.
+func Serve(ctx context.Context, r *route.Router, m *autocert.Manager, opts ...route.ServeOptions) error {
+	Register(r, m)
+	return r.ServeListeners(ctx, []route.Listener{
+		{Addr: ":https", TLSConfig: m.TLSConfig()},
+		{Addr: ":http", Handler: HTTPHandler(r)},
+	}, opts...)
+}
+
+// HTTPHandler returns the handler Serve uses for plain HTTP: requests
+// for challenges are served by r, on which Register must have been
+// called, and others are redirected to the same URL on HTTPS.
+func HTTPHandler(r *route.Router) http.Handler {
+	prefix := ChallengePath[:strings.LastIndex(ChallengePath, "/")+1]
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			r.ServeHTTP(w, req)
+			return
+		}
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		code := http.StatusMovedPermanently
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			code = http.StatusPermanentRedirect
+		}
+		http.Redirect(w, req, "https://"+host+req.URL.RequestURI(), code)
+	})
+}
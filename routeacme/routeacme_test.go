@@ -0,0 +1,45 @@
+package routeacme
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestRegister(t *testing.T) {
+	r := &route.Router{}
+	r.Route("/*").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		w.Write([]byte("app"))
+	})
+	Register(r, &autocert.Manager{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/acme-challenge/abc", nil))
+	// No challenge is pending, so autocert answers 404 rather than the
+	// catch-all route answering.
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotEqual(t, "app", w.Body.String())
+}
+
+func TestHTTPHandler(t *testing.T) {
+	r := &route.Router{}
+	Register(r, &autocert.Manager{})
+	h := HTTPHandler(r)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com:80/a?b=c", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://example.com/a?b=c", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://example.com/a", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/.well-known/acme-challenge/abc", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,49 @@
+// Package routedebug mounts the standard library's debugging handlers,
+// from net/http/pprof and expvar, on a route.Router, under
+// router-controlled subtrees that middleware can protect:
+//
+//     routedebug.MountPprof(r, "/admin/pprof").Use(requireAdmin)
+//     routedebug.MountExpvar(r, "/admin/vars").Use(requireAdmin)
+//
+// It is a separate package because importing net/http/pprof and expvar
+// registers their handlers on http.DefaultServeMux, which programs
+// using only the router shouldn't pay for.
+package routedebug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/evmar/route"
+)
+
+// MountPprof registers the profiling handlers of net/http/pprof
+// beneath path in r, which need not be "/debug/pprof", and returns the
+// router for path.  "path/" serves the index of profiles, to which
+// "path" redirects, and "path/heap", "path/goroutine" and so on serve
+// the profiles.
+func MountPprof(r *route.Router, path string) *route.Router {
+	n := r.Route(path)
+	n.Method(http.MethodGet).FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		http.Redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
+	})
+	n.Route("/").Func(pprof.Index)
+	n.Route("/cmdline").Func(pprof.Cmdline)
+	n.Route("/profile").Func(pprof.Profile)
+	n.Route("/symbol").Func(pprof.Symbol)
+	n.Route("/trace").Func(pprof.Trace)
+	// pprof.Index only serves named profiles beneath "/debug/pprof/".
+	n.Route("/:profile").FuncE(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		pprof.Handler(env["profile"]).ServeHTTP(w, req)
+	})
+	return n
+}
+
+// MountExpvar registers the expvar handler, serving the published
+// variables as JSON, at path in r, and returns the router for path.
+func MountExpvar(r *route.Router, path string) *route.Router {
+	n := r.Route(path)
+	n.Method(http.MethodGet).Func(expvar.Handler().ServeHTTP)
+	return n
+}
@@ -0,0 +1,57 @@
+package routedebug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestMountPprof(t *testing.T) {
+	r := &route.Router{}
+	denied := false
+	MountPprof(r, "/admin/pprof").Use(func(next route.HandlerE) route.HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if denied {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next(w, req, env)
+		}
+	})
+
+	w := get(r, "/admin/pprof")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/admin/pprof/", w.Header().Get("Location"))
+
+	w = get(r, "/admin/pprof/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "goroutine"))
+
+	w = get(r, "/admin/pprof/goroutine?debug=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "goroutine profile:"))
+
+	assert.Equal(t, http.StatusOK, get(r, "/admin/pprof/cmdline").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/admin/pprof/nosuchprofile").Code)
+
+	denied = true
+	assert.Equal(t, http.StatusForbidden, get(r, "/admin/pprof/heap").Code)
+}
+
+func TestMountExpvar(t *testing.T) {
+	r := &route.Router{}
+	MountExpvar(r, "/admin/vars")
+	w := get(r, "/admin/vars")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), `"memstats"`))
+}
@@ -0,0 +1,67 @@
+// Package routetest provides assertions for unit testing route tables,
+// without serving requests:
+//
+//     func TestRoutes(t *testing.T) {
+//         r := newRouter()
+//         routetest.AssertMatch(t, r, "GET /users/5", "users.show", map[string]string{"id": "5"})
+//         routetest.AssertNoMatch(t, r, "DELETE /users/5")
+//     }
+package routetest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/evmar/route"
+)
+
+// parse splits a request like "GET /users/5" into its method and
+// path.  A request with no method is a GET.
+func parse(request string) (method, path string) {
+	if i := strings.IndexByte(request, ' '); i >= 0 {
+		return request[:i], strings.TrimSpace(request[i+1:])
+	}
+	return "GET", request
+}
+
+// AssertMatch checks that r routes request, like "GET /users/5", to
+// the route identified by want, capturing vars.  want may be the
+// route's name (see route.Router.Name), its pattern, or the name of
+// its handler function.  Pass nil vars for a route that captures
+// nothing.
+//
+// It reports whether the assertion held.
+func AssertMatch(t testing.TB, r *route.Router, request, want string, vars map[string]string) bool {
+	t.Helper()
+	method, path := parse(request)
+	m, ok := r.Match(method, path)
+	if !ok {
+		t.Errorf("%s: no route matches", request)
+		return false
+	}
+	if want != m.Name && want != m.Pattern && want != m.HandlerName {
+		t.Errorf("%s: matched %s (name %q, handler %s), want %s", request, m.Pattern, m.Name, m.HandlerName, want)
+		return false
+	}
+	if len(vars) == 0 && len(m.Vars) == 0 {
+		return true
+	}
+	if !reflect.DeepEqual(vars, m.Vars) {
+		t.Errorf("%s: captured %v, want %v", request, m.Vars, vars)
+		return false
+	}
+	return true
+}
+
+// AssertNoMatch checks that r routes request, like "DELETE /users/5",
+// nowhere.  It reports whether the assertion held.
+func AssertNoMatch(t testing.TB, r *route.Router, request string) bool {
+	t.Helper()
+	method, path := parse(request)
+	if m, ok := r.Match(method, path); ok {
+		t.Errorf("%s: matched %s, want no match", request, m.Pattern)
+		return false
+	}
+	return true
+}
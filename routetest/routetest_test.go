@@ -0,0 +1,48 @@
+package routetest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/evmar/route"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a testing.TB that records failures rather than failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func show(w http.ResponseWriter, r *http.Request, env map[string]string) {}
+
+func TestAssertMatch(t *testing.T) {
+	r := &route.Router{}
+	r.Route("/users/:id").Name("users.show").Method("GET").FuncE(show)
+	r.Route("/about").FuncE(show)
+
+	rec := &recorder{TB: t}
+	assert.True(t, AssertMatch(rec, r, "GET /users/5", "users.show", map[string]string{"id": "5"}))
+	assert.True(t, AssertMatch(rec, r, "/users/5", "/users/:id", map[string]string{"id": "5"}))
+	assert.True(t, AssertMatch(rec, r, "POST /about", "github.com/evmar/route/routetest.show", nil))
+	assert.True(t, AssertNoMatch(rec, r, "DELETE /users/5"))
+	assert.Nil(t, rec.errors)
+
+	assert.False(t, AssertMatch(rec, r, "GET /nope", "users.show", nil))
+	assert.False(t, AssertMatch(rec, r, "GET /about", "users.show", nil))
+	assert.False(t, AssertMatch(rec, r, "GET /users/5", "users.show", map[string]string{"id": "6"}))
+	assert.False(t, AssertNoMatch(rec, r, "GET /about"))
+	assert.Equal(t, []string{
+		"GET /nope: no route matches",
+		`GET /about: matched /about (name "", handler github.com/evmar/route/routetest.show), want users.show`,
+		"GET /users/5: captured map[id:5], want map[id:6]",
+		"GET /about: matched /about, want no match",
+	}, rec.errors)
+}
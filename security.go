@@ -0,0 +1,101 @@
+package route
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SecurityHeaders is middleware adding the usual security headers to
+// responses:
+//
+//     r.Use(route.SecurityHeaders{
+//         HSTSMaxAge:            365 * 24 * time.Hour,
+//         ContentSecurityPolicy: "default-src 'self'",
+//     }.Middleware())
+//
+// Headers are set before the handler runs, so handlers can override
+// them, and a subtree can relax one with SetHeader, or with
+// SecurityHeaders of its own:
+//
+//     r.Route("/admin").SetHeader("Content-Security-Policy", "default-src 'self' 'unsafe-inline'")
+//     r.Route("/embed").Use(route.SecurityHeaders{FrameOptions: "-"}.Middleware())
+//
+// X-Content-Type-Options is always "nosniff".  For the others, the
+// zero value gives the default noted, and "-" omits the header,
+// removing it if SecurityHeaders nearer the root set it; a negative
+// HSTSMaxAge does the same for Strict-Transport-Security.  Since
+// Content-Security-Policy and Strict-Transport-Security have no
+// default, a subtree's SecurityHeaders leaves those set nearer the
+// root in place unless it sets or removes them itself.
+type SecurityHeaders struct {
+	// HSTSMaxAge, if positive, makes browsers use only HTTPS for the
+	// site for that long, with Strict-Transport-Security, which is
+	// sent on HTTPS requests.  It may cover subdomains, and the site
+	// may ask to be preloaded into browsers.  If negative, the header
+	// is removed.
+	HSTSMaxAge     time.Duration
+	HSTSSubdomains bool
+	HSTSPreload    bool
+
+	// FrameOptions is the X-Frame-Options header; "DENY" by default.
+	FrameOptions string
+
+	// ReferrerPolicy is the Referrer-Policy header;
+	// "strict-origin-when-cross-origin" by default.
+	ReferrerPolicy string
+
+	// ContentSecurityPolicy is the Content-Security-Policy header; it
+	// is left out by default, as a policy must fit the site.
+	ContentSecurityPolicy string
+}
+
+// Middleware returns middleware applying s.
+func (s SecurityHeaders) Middleware() Middleware {
+	hsts := ""
+	if s.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(s.HSTSMaxAge/time.Second), 10)
+		if s.HSTSSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if s.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	// An empty value in headers removes the header.
+	headers := [][2]string{{"X-Content-Type-Options", "nosniff"}}
+	for _, h := range [][3]string{
+		{"X-Frame-Options", s.FrameOptions, "DENY"},
+		{"Referrer-Policy", s.ReferrerPolicy, "strict-origin-when-cross-origin"},
+		{"Content-Security-Policy", s.ContentSecurityPolicy, ""},
+	} {
+		switch v := h[1]; v {
+		case "-":
+			headers = append(headers, [2]string{h[0], ""})
+		case "":
+			if h[2] != "" {
+				headers = append(headers, [2]string{h[0], h[2]})
+			}
+		default:
+			headers = append(headers, [2]string{h[0], v})
+		}
+	}
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			wh := w.Header()
+			for _, h := range headers {
+				if h[1] == "" {
+					wh.Del(h[0])
+				} else {
+					wh.Set(h[0], h[1])
+				}
+			}
+			if hsts != "" && req.TLS != nil {
+				wh.Set("Strict-Transport-Security", hsts)
+			} else if s.HSTSMaxAge < 0 {
+				wh.Del("Strict-Transport-Security")
+			}
+			next(w, req, env)
+		}
+	}
+}
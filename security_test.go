@@ -0,0 +1,54 @@
+package route
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	r := &Router{}
+	r.Use(SecurityHeaders{
+		HSTSMaxAge:            24 * time.Hour,
+		HSTSSubdomains:        true,
+		ContentSecurityPolicy: "default-src 'self'",
+	}.Middleware())
+	r.Route("/").FuncE(F1)
+	r.Route("/admin").SetHeader("Content-Security-Policy", "default-src 'self' 'unsafe-inline'").FuncE(F1)
+	r.Route("/embed").Use(SecurityHeaders{FrameOptions: "-", ReferrerPolicy: "no-referrer"}.Middleware()).FuncE(F1)
+	r.Route("/legacy").Use(SecurityHeaders{HSTSMaxAge: -1, ContentSecurityPolicy: "-"}.Middleware()).FuncE(F1)
+
+	h := get(r, "/").Header()
+	assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", h.Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", h.Get("Referrer-Policy"))
+	assert.Equal(t, "default-src 'self'", h.Get("Content-Security-Policy"))
+	// HSTS is only sent over HTTPS.
+	assert.Equal(t, "", h.Get("Strict-Transport-Security"))
+
+	req := httptest.NewRequest("GET", "https://example.com/", nil)
+	req.TLS = &tls.ConnectionState{}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "max-age=86400; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+
+	assert.Equal(t, "default-src 'self' 'unsafe-inline'", get(r, "/admin").Header().Get("Content-Security-Policy"))
+
+	// The subtree's SecurityHeaders replaces the headers it sets.
+	h = get(r, "/embed").Header()
+	assert.Equal(t, "", h.Get("X-Frame-Options"))
+	assert.Equal(t, "default-src 'self'", h.Get("Content-Security-Policy"))
+	assert.Equal(t, "no-referrer", h.Get("Referrer-Policy"))
+
+	// "-" and a negative HSTSMaxAge remove headers set nearer the root.
+	req = httptest.NewRequest("GET", "https://example.com/legacy", nil)
+	req.TLS = &tls.ConnectionState{}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+}
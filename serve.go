@@ -0,0 +1,271 @@
+package route
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ServeOptions configures the servers started by Serve and its
+// relatives.  Zero durations take the defaults given.
+type ServeOptions struct {
+	// ReadHeaderTimeout limits the time to read request headers,
+	// protecting against slow clients; 10 seconds by default.
+	ReadHeaderTimeout time.Duration
+	// IdleTimeout limits how long keep-alive connections wait for the
+	// next request; 2 minutes by default.
+	IdleTimeout time.Duration
+	// ReadTimeout and WriteTimeout limit the time to read a whole
+	// request and to write the response.  They are off by default, as
+	// they would cut off streaming responses and slow uploads; use
+	// Timeout and MaxBodySize to limit routes individually.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// ShutdownTimeout limits how long shutdown waits for requests in
+	// flight to finish; 30 seconds by default.
+	ShutdownTimeout time.Duration
+	// H2C serves HTTP/2 over plain TCP ("h2c") alongside HTTP/1, for
+	// routers behind load balancers that speak HTTP/2 to their
+	// backends without TLS.  Listeners serving HTTPS offer HTTP/2
+	// regardless.
+	H2C bool
+}
+
+// Server returns an http.Server serving r on addr with the timeouts
+// of o, for programs needing more control than Serve offers.
+func (r *Router) Server(addr string, o ServeOptions) *http.Server {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           r,
+		ReadHeaderTimeout: o.ReadHeaderTimeout,
+		IdleTimeout:       o.IdleTimeout,
+		ReadTimeout:       o.ReadTimeout,
+		WriteTimeout:      o.WriteTimeout,
+	}
+	if srv.ReadHeaderTimeout == 0 {
+		srv.ReadHeaderTimeout = 10 * time.Second
+	}
+	if srv.IdleTimeout == 0 {
+		srv.IdleTimeout = 2 * time.Minute
+	}
+	if o.H2C {
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetHTTP2(true)
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return srv
+}
+
+// Serve serves r on addr until ctx is done or the process gets SIGTERM
+// or an interrupt, then shuts down gracefully, letting requests in
+// flight finish.  It returns nil after a graceful shutdown, so small
+// services need no more than:
+//
+//     log.Fatal(r.Serve(context.Background(), ":8080"))
+//
+// The server has sane timeouts; see ServeOptions.  At most one
+// ServeOptions may be given.
+func (r *Router) Serve(ctx context.Context, addr string, opts ...ServeOptions) error {
+	return r.listenAndServe(ctx, addr, "", "", opts)
+}
+
+// ListenAndServe is Serve with a background context, as a replacement
+// for http.ListenAndServe.
+func (r *Router) ListenAndServe(addr string, opts ...ServeOptions) error {
+	return r.listenAndServe(context.Background(), addr, "", "", opts)
+}
+
+// ListenAndServeTLS is like ListenAndServe, but serves HTTPS with the
+// certificate and key in the given files, as by
+// http.ListenAndServeTLS.
+func (r *Router) ListenAndServeTLS(addr, certFile, keyFile string, opts ...ServeOptions) error {
+	return r.listenAndServe(context.Background(), addr, certFile, keyFile, opts)
+}
+
+func (r *Router) listenAndServe(ctx context.Context, addr, certFile, keyFile string, opts []ServeOptions) error {
+	if addr == "" {
+		addr = ":http"
+		if certFile != "" {
+			addr = ":https"
+		}
+	}
+	return r.ServeListeners(ctx, []Listener{{Addr: addr, CertFile: certFile, KeyFile: keyFile}}, opts...)
+}
+
+// Listener is an address served by ServeListeners.
+type Listener struct {
+	// Addr is a TCP address, like ":8080", or the path of a Unix
+	// domain socket, like "unix:///run/app.sock".  A stale socket
+	// left by an earlier process is removed.
+	Addr string
+
+	// CertFile and KeyFile, if set, name the certificate and key to
+	// serve HTTPS with.  Alternatively, TLSConfig may supply them, as
+	// with a GetCertificate function.
+	CertFile, KeyFile string
+	TLSConfig         *tls.Config
+
+	// RedirectHTTPS answers every request with a redirect to the same
+	// URL on HTTPS, on the port of the first Listener serving HTTPS,
+	// rather than serving the router.
+	RedirectHTTPS bool
+
+	// Handler, if set, serves requests in place of the router.
+	Handler http.Handler
+}
+
+// tls reports whether l serves HTTPS.
+func (l *Listener) tls() bool {
+	return l.CertFile != "" || l.TLSConfig != nil
+}
+
+// ServeListeners is like Serve, but serves r on several addresses at
+// once, shutting them all down together.  For example, to serve HTTPS
+// and redirect plain HTTP to it:
+//
+//     r.ServeListeners(ctx, []route.Listener{
+//         {Addr: ":443", CertFile: "cert.pem", KeyFile: "key.pem"},
+//         {Addr: ":80", RedirectHTTPS: true},
+//     })
+//
+// Addresses may also be Unix domain sockets, as for a sidecar behind
+// a local reverse proxy.  If any address fails to listen or serve,
+// the others are shut down and the error returned.
+func (r *Router) ServeListeners(ctx context.Context, ls []Listener, opts ...ServeOptions) error {
+	if len(opts) > 1 {
+		panic("route: Serve takes at most one ServeOptions")
+	}
+	var o ServeOptions
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+	httpsPort := ""
+	for _, l := range ls {
+		if l.tls() {
+			if _, port, err := net.SplitHostPort(l.Addr); err == nil && port != "443" && port != "https" {
+				httpsPort = port
+			}
+			break
+		}
+	}
+
+	var servers []serving
+	defer func() {
+		for _, s := range servers {
+			s.ln.Close()
+		}
+	}()
+	for _, l := range ls {
+		ln, err := listen(l.Addr)
+		if err != nil {
+			return err
+		}
+		srv := r.Server(l.Addr, o)
+		srv.TLSConfig = l.TLSConfig
+		if l.RedirectHTTPS {
+			srv.Handler = redirectHTTPS(httpsPort)
+		}
+		if l.Handler != nil {
+			srv.Handler = l.Handler
+		}
+		servers = append(servers, serving{srv, ln, l.tls(), l.CertFile, l.KeyFile})
+	}
+	return serveUntilDone(ctx, servers, o.ShutdownTimeout)
+}
+
+// listen listens on addr, a TCP address or "unix://" socket path.
+func listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	path = strings.TrimPrefix(path, "//")
+	if st, err := os.Stat(path); err == nil && st.Mode()&os.ModeSocket != 0 {
+		// A socket no process is listening on, left by a crash.
+		if c, err := net.Dial("unix", path); err == nil {
+			c.Close()
+		} else {
+			os.Remove(path)
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+// redirectHTTPS returns a handler redirecting requests to HTTPS on
+// port, or the default port if port is empty.
+func redirectHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" {
+			host = net.JoinHostPort(host, port)
+		}
+		code := http.StatusMovedPermanently
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			code = http.StatusPermanentRedirect
+		}
+		http.Redirect(w, req, "https://"+host+req.URL.RequestURI(), code)
+	})
+}
+
+// serving is a server and the listener it serves, over TLS if tls is
+// set.
+type serving struct {
+	srv               *http.Server
+	ln                net.Listener
+	tls               bool
+	certFile, keyFile string
+}
+
+// serveUntilDone runs servers until ctx is done, the process is
+// signalled to stop, or one of them fails, then shuts them all down,
+// allowing grace for requests in flight.
+func serveUntilDone(ctx context.Context, servers []serving, grace time.Duration) error {
+	if grace == 0 {
+		grace = 30 * time.Second
+	}
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	errc := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s serving) {
+			if s.tls {
+				errc <- s.srv.ServeTLS(s.ln, s.certFile, s.keyFile)
+			} else {
+				errc <- s.srv.Serve(s.ln)
+			}
+		}(s)
+	}
+	var err error
+	pending := len(servers)
+	select {
+	case err = <-errc:
+		pending--
+	case <-ctx.Done():
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	for _, s := range servers {
+		if serr := s.srv.Shutdown(sctx); err == nil {
+			err = serr
+		}
+	}
+	for ; pending > 0; pending-- {
+		if serr := <-errc; err == nil && !errors.Is(serr, http.ErrServerClosed) {
+			err = serr
+		}
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package route
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeUntilDone(t *testing.T) {
+	r := &Router{}
+	started := make(chan struct{})
+	r.Route("/slow").Func(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("finished"))
+	})
+	srv := r.Server("", ServeOptions{})
+	assert.Equal(t, 10*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, time.Duration(0), srv.WriteTimeout)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveUntilDone(ctx, []serving{{srv, ln, false, "", ""}}, 0) }()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+
+	// The request in flight finishes despite the shutdown.
+	<-started
+	cancel()
+	assert.Equal(t, "finished", <-body)
+	assert.Nil(t, <-done)
+
+	_, err = http.Get("http://" + ln.Addr().String() + "/slow")
+	assert.NotNil(t, err)
+}
+
+func TestServeListeners(t *testing.T) {
+	r := &Router{}
+	r.Route("/").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	sock := filepath.Join(t.TempDir(), "app.sock")
+	// A stale socket from an earlier process is replaced.
+	stale, err := net.Listen("unix", sock)
+	assert.Nil(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.ServeListeners(ctx, []Listener{{Addr: "unix://" + sock}}) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return net.Dial("unix", sock)
+		},
+	}}
+	var resp *http.Response
+	for i := 0; i < 100; i++ {
+		if resp, err = client.Get("http://app/"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Nil(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "hello", string(b))
+	cancel()
+	assert.Nil(t, <-done)
+
+	// A listener failing, here to load its certificate, takes down
+	// the others.
+	err = r.ServeListeners(context.Background(), []Listener{
+		{Addr: "unix://" + sock},
+		{Addr: "127.0.0.1:0", CertFile: "missing.pem", KeyFile: "missing.pem"},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestRedirectHTTPS(t *testing.T) {
+	w := httptest.NewRecorder()
+	redirectHTTPS("8443").ServeHTTP(w, httptest.NewRequest("GET", "http://example.com:8080/a?b=c", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://example.com:8443/a?b=c", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	redirectHTTPS("").ServeHTTP(w, httptest.NewRequest("POST", "http://example.com/form", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "https://example.com/form", w.Header().Get("Location"))
+}
+
+func TestServeH2C(t *testing.T) {
+	r := &Router{}
+	r.Route("/").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.Proto))
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	srv := r.Server("", ServeOptions{H2C: true})
+	go func() { done <- serveUntilDone(ctx, []serving{{srv, ln, false, "", ""}}, 0) }()
+
+	var protos http.Protocols
+	protos.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: &protos}}
+	resp, err := client.Get("http://" + ln.Addr().String() + "/")
+	assert.Nil(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "HTTP/2.0", string(b))
+
+	// HTTP/1 is still served.
+	resp, err = http.Get("http://" + ln.Addr().String() + "/")
+	assert.Nil(t, err)
+	b, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "HTTP/1.1", string(b))
+
+	cancel()
+	assert.Nil(t, <-done)
+}
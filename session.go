@@ -0,0 +1,256 @@
+package route
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// SessionStore loads and saves the values of sessions, for the
+// Sessions middleware.  CookieStore keeps them in the client's cookie;
+// a store on a server, like Redis, keeps them under an ID that it
+// manages in a cookie of its own.
+type SessionStore interface {
+	// Load returns the values of the request's session, or an empty
+	// or nil map for a new session.  Sessions that are invalid or
+	// expired should be treated as new, without an error.
+	Load(req *http.Request) (map[string]string, error)
+	// Save stores the values of the request's session, which are
+	// empty if the session was cleared, setting any cookies on w.
+	Save(w http.ResponseWriter, req *http.Request, values map[string]string) error
+}
+
+// Sessions is middleware giving handlers beneath it a session, through
+// Session:
+//
+//     r.Use(route.Sessions{Store: &route.CookieStore{Key: key}}.Middleware())
+//
+// Each request's session is loaded before the handler runs, so
+// middleware further down the chain, like authorization checks, can
+// rely on it.  If the handler changes the session, it is saved just
+// before the response headers are written.  A request whose session
+// can't be loaded gets a 500 Internal Server Error.
+type Sessions struct {
+	Store SessionStore
+}
+
+// sessionKey is the context key of a request's *SessionData.
+type sessionKey struct{}
+
+// Middleware returns middleware applying s.
+func (s Sessions) Middleware() Middleware {
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			values, err := s.Store.Load(req)
+			if err != nil {
+				logf(req, "loading session: %v", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if values == nil {
+				values = make(map[string]string)
+			}
+			sd := &SessionData{values: values}
+			req = req.WithContext(context.WithValue(req.Context(), sessionKey{}, sd))
+			sw := &sessionWriter{ResponseWriter: w, save: func() {
+				if sd.changed {
+					if err := s.Store.Save(w, req, sd.values); err != nil {
+						logf(req, "saving session: %v", err)
+					}
+				}
+			}}
+			next(sw, req, env)
+			sw.saveOnce()
+		}
+	}
+}
+
+// Session returns the session of req, given by the Sessions
+// middleware, or nil if there is none.
+func Session(req *http.Request) *SessionData {
+	sd, _ := req.Context().Value(sessionKey{}).(*SessionData)
+	return sd
+}
+
+// SessionData holds the values of a session; see Session.  Changes
+// made after the response headers have been written are lost.  Its
+// methods must not be called concurrently.
+type SessionData struct {
+	values  map[string]string
+	changed bool
+}
+
+// Get returns the value of key, or "" if it is not set.
+func (sd *SessionData) Get(key string) string {
+	return sd.values[key]
+}
+
+// Set sets key to value.
+func (sd *SessionData) Set(key, value string) {
+	sd.values[key] = value
+	sd.changed = true
+}
+
+// Delete removes key.
+func (sd *SessionData) Delete(key string) {
+	if _, ok := sd.values[key]; ok {
+		delete(sd.values, key)
+		sd.changed = true
+	}
+}
+
+// Clear removes every value, as when logging out.
+func (sd *SessionData) Clear() {
+	clear(sd.values)
+	sd.changed = true
+}
+
+// sessionWriter saves the session before the response headers are
+// written.
+type sessionWriter struct {
+	http.ResponseWriter
+	save  func()
+	saved bool
+}
+
+func (sw *sessionWriter) saveOnce() {
+	if !sw.saved {
+		sw.saved = true
+		sw.save()
+	}
+}
+
+func (sw *sessionWriter) WriteHeader(code int) {
+	sw.saveOnce()
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *sessionWriter) Write(p []byte) (int, error) {
+	sw.saveOnce()
+	return sw.ResponseWriter.Write(p)
+}
+
+func (sw *sessionWriter) Unwrap() http.ResponseWriter { return sw.ResponseWriter }
+
+// CookieStore is a SessionStore keeping sessions in a cookie,
+// encrypted and authenticated with AES-GCM, so clients can neither
+// read nor forge them.  Cookies are limited to about 4KB, so sessions
+// should hold little more than IDs.
+type CookieStore struct {
+	// Key is the AES key, of 16, 24 or 32 bytes.  It must be kept
+	// secret.
+	Key []byte
+
+	// Name is the name of the cookie; "session" by default.
+	Name string
+
+	// MaxAge limits how long a session lasts after it was last saved.
+	// If zero, sessions last until the browser is closed.
+	MaxAge time.Duration
+
+	// Cookie attributes.  Cookies are always HttpOnly, and SameSite
+	// defaults to Lax.
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// cookieSession is the plaintext of a CookieStore cookie.
+type cookieSession struct {
+	Values  map[string]string `json:"v"`
+	Expires int64             `json:"e,omitempty"`
+}
+
+func (c *CookieStore) name() string {
+	if c.Name == "" {
+		return "session"
+	}
+	return c.Name
+}
+
+func (c *CookieStore) aead() cipher.AEAD {
+	block, err := aes.NewCipher(c.Key)
+	if err != nil {
+		panic("route: bad CookieStore key: " + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err.Error())
+	}
+	return aead
+}
+
+// Load implements SessionStore.
+func (c *CookieStore) Load(req *http.Request) (map[string]string, error) {
+	aead := c.aead()
+	cookie, err := req.Cookie(c.name())
+	if err != nil {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil || len(b) < aead.NonceSize() {
+		return nil, nil
+	}
+	plain, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], []byte(c.name()))
+	if err != nil {
+		return nil, nil
+	}
+	var cs cookieSession
+	if err := json.Unmarshal(plain, &cs); err != nil {
+		return nil, nil
+	}
+	if cs.Expires != 0 && timeNow().Unix() >= cs.Expires {
+		return nil, nil
+	}
+	return cs.Values, nil
+}
+
+// Save implements SessionStore.
+func (c *CookieStore) Save(w http.ResponseWriter, req *http.Request, values map[string]string) error {
+	cookie := &http.Cookie{
+		Name:     c.name(),
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	if len(values) == 0 {
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+		return nil
+	}
+	cs := cookieSession{Values: values}
+	if c.MaxAge > 0 {
+		cookie.MaxAge = int(c.MaxAge / time.Second)
+		cs.Expires = timeNow().Add(c.MaxAge).Unix()
+	}
+	plain, err := json.Marshal(cs)
+	if err != nil {
+		return err
+	}
+	aead := c.aead()
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	cookie.Value = base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, []byte(c.name())))
+	if len(cookie.Value) > 4000 {
+		return errors.New("session too large for a cookie")
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
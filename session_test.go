@@ -0,0 +1,72 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withCookies returns a GET request for path carrying the cookies set
+// by an earlier response.
+func withCookies(path string, prev *httptest.ResponseRecorder) *http.Request {
+	req := httptest.NewRequest("GET", path, nil)
+	for _, c := range prev.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func TestSessions(t *testing.T) {
+	now := fakeClock(t)
+	store := &CookieStore{Key: make([]byte, 32), MaxAge: time.Hour}
+	r := &Router{}
+	r.Use(Sessions{Store: store}.Middleware())
+	r.Route("/login").Func(func(w http.ResponseWriter, req *http.Request) {
+		Session(req).Set("user", "ann")
+		w.Write([]byte("welcome"))
+	})
+	r.Route("/me").Func(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(Session(req).Get("user")))
+	})
+	r.Route("/logout").Func(func(w http.ResponseWriter, req *http.Request) {
+		Session(req).Clear()
+	})
+
+	login := httptest.NewRecorder()
+	r.ServeHTTP(login, httptest.NewRequest("GET", "/login", nil))
+	assert.Equal(t, "welcome", login.Body.String())
+	cookies := login.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, 3600, cookies[0].MaxAge)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, withCookies("/me", login))
+	assert.Equal(t, "ann", w.Body.String())
+	// Unchanged sessions aren't saved again.
+	assert.Equal(t, 0, len(w.Result().Cookies()))
+
+	// Tampered and expired cookies start new sessions.
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: cookies[0].Value[:len(cookies[0].Value)-2] + "AA"})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "", w.Body.String())
+
+	*now = now.Add(2 * time.Hour)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, withCookies("/me", login))
+	assert.Equal(t, "", w.Body.String())
+
+	// Clearing deletes the cookie, even without a body.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, withCookies("/logout", login))
+	assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+}
+
+func TestSessionWithoutMiddleware(t *testing.T) {
+	assert.Nil(t, Session(httptest.NewRequest("GET", "/", nil)))
+}
@@ -0,0 +1,74 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedURL(t *testing.T) {
+	now := fakeClock(t)
+	r := &Router{SigningKey: []byte("secret")}
+	r.Route("/files/:id").Name("file").RequireSignedURL().FuncE(F1)
+
+	u, err := r.SignedURL("file", time.Hour, 7)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(u, "/files/7?expires=1767229200&signature="), u)
+	assert.Equal(t, http.StatusOK, get(r, u).Code)
+
+	// Extra query parameters before the signature are covered by it.
+	path, query, _ := strings.Cut(u, "?")
+	extra := path + "?" + "dl=1&" + query[:strings.Index(query, "&signature=")]
+	assert.Equal(t, http.StatusOK, get(r, extra+"&signature="+urlSignature(r.SigningKey, extra)).Code)
+
+	for _, bad := range []string{
+		"/files/7",
+		strings.Replace(u, "/7?", "/8?", 1),
+		strings.Replace(u, "expires=1767229200", "expires=1767229201", 1),
+		u + "&more=1",
+		u[:len(u)-1],
+	} {
+		assert.Equal(t, http.StatusForbidden, get(r, bad).Code, bad)
+	}
+
+	*now = now.Add(time.Hour)
+	w := get(r, u)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "link expired\n", w.Body.String())
+
+	_, err = (&Router{}).SignedURL("file", time.Hour)
+	assert.NotNil(t, err)
+}
+
+func TestSignedURLLocales(t *testing.T) {
+	fakeClock(t)
+	r := &Router{SigningKey: []byte("secret")}
+	r.Locales("en", "fr")
+	r.Route("/confirm/:id").Name("confirm").RequireSignedURL().FuncE(F1)
+
+	u, err := r.SignedURL("confirm", time.Hour, 42)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(u, "/en/confirm/42?expires="), u)
+	assert.Equal(t, http.StatusOK, get(r, u).Code)
+	assert.Equal(t, http.StatusOK, get(r, "/fr"+strings.TrimPrefix(u, "/en")).Code)
+	assert.Equal(t, http.StatusOK, get(r, strings.TrimPrefix(u, "/en")).Code)
+	assert.Equal(t, http.StatusForbidden, get(r, strings.Replace(u, "/42?", "/43?", 1)).Code)
+}
+
+func TestSignedURLEscaping(t *testing.T) {
+	fakeClock(t)
+	r := &Router{SigningKey: []byte("secret")}
+	r.Locales("en", "fr")
+	r.Rewrite("/dl/*", "/files/*")
+	r.Route("/files/:name").Name("file").RequireSignedURL().FuncE(F1)
+
+	u, err := r.SignedURL("file", time.Hour, "a,b;c:d e")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, get(r, u).Code, u)
+	assert.Equal(t, http.StatusOK, get(r, strings.TrimPrefix(u, "/en")).Code, u)
+	assert.Equal(t, http.StatusOK, get(r, strings.Replace(u, "/en/files/", "/fr/dl/", 1)).Code, u)
+	assert.Equal(t, http.StatusForbidden, get(r, strings.Replace(u, "a%2Cb", "a%2Cc", 1)).Code, u)
+}
@@ -0,0 +1,156 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event is a server-sent event; see SSE.
+type Event struct {
+	// ID, if set, is sent as the event's id, which the client echoes
+	// in Last-Event-ID when it reconnects.  Line breaks are removed.
+	ID string
+	// Event is the event's type; if empty, clients see "message".
+	// Line breaks are removed.
+	Event string
+	// Data is the event's payload.  It may span several lines.
+	Data string
+	// Retry, if non-zero, tells the client how long to wait before
+	// reconnecting.
+	Retry time.Duration
+}
+
+// lastEventIDKey is the context key under which SSE records the
+// request's Last-Event-ID.
+type lastEventIDKey struct{}
+
+// LastEventID returns the ID of the last event received by a
+// reconnecting client, from the Last-Event-ID header of the request
+// whose context is ctx, or "" for a new client.  See SSE.
+func LastEventID(ctx context.Context) string {
+	id, _ := ctx.Value(lastEventIDKey{}).(string)
+	return id
+}
+
+// SSE registers f to stream server-sent events at the current point.
+// Each request calls f, which sends events until it returns or ctx is
+// done, meaning the client went away:
+//
+//     r.Route("/events").SSE(func(ctx context.Context, send chan<- route.Event, env map[string]string) {
+//         for _, ev := range backlog(route.LastEventID(ctx)) {
+//             send <- ev
+//         }
+//         for {
+//             select {
+//             case ev := <-updates:
+//                 send <- ev
+//             case <-ctx.Done():
+//                 return
+//             }
+//         }
+//     })
+//
+// The response gets the text/event-stream headers and is flushed
+// after every event.  Clients resume after reconnecting from the event
+// named by LastEventID.  f must not close send, but need not watch ctx
+// while sending: events sent after the client leaves are dropped.
+// Writers that can't flush get a 500 Internal Server Error.  A panic
+// in f is passed on to the goroutine serving the request.
+func (r *Router) SSE(f func(ctx context.Context, send chan<- Event, env map[string]string)) {
+	h := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		if !flushable(w) {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		rc.Flush()
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		ctx = context.WithValue(ctx, lastEventIDKey{}, req.Header.Get("Last-Event-ID"))
+		send := make(chan Event)
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer close(done)
+			defer func() {
+				if v := recover(); v != nil {
+					panicked <- v
+				}
+			}()
+			f(ctx, send, env)
+		}()
+		for {
+			select {
+			case ev := <-send:
+				if ctx.Err() != nil {
+					continue
+				}
+				if _, err := w.Write(ev.encode()); err != nil {
+					cancel()
+				} else if err := rc.Flush(); err != nil {
+					cancel()
+				}
+			case <-done:
+				select {
+				case v := <-panicked:
+					panic(v)
+				default:
+				}
+				return
+			}
+		}
+	}
+	defer r.lock()()
+	if err := r.setHandler(h, funcName(f)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// flushable reports whether w, or a writer it wraps, can be flushed.
+func flushable(w http.ResponseWriter) bool {
+	for {
+		switch t := w.(type) {
+		case http.Flusher:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// stripNewlines removes line breaks from single-line fields, which
+// would otherwise let them inject fields of their own.
+var stripNewlines = strings.NewReplacer("\r", "", "\n", "")
+
+// dataNewlines normalizes the line breaks in Data to "\n", since a
+// client ends a line at any of "\r\n", "\r" and "\n".
+var dataNewlines = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// encode returns ev in the event stream format.
+func (ev Event) encode() []byte {
+	var b strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", stripNewlines.Replace(ev.ID))
+	}
+	if ev.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", stripNewlines.Replace(ev.Event))
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(dataNewlines.Replace(ev.Data), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,75 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSE(t *testing.T) {
+	r := &Router{}
+	r.Route("/events/:topic").SSE(func(ctx context.Context, send chan<- Event, env map[string]string) {
+		send <- Event{ID: "1", Event: env["topic"], Data: "resume after " + LastEventID(ctx)}
+		send <- Event{Data: "two\nlines", Retry: 3 * time.Second}
+	})
+
+	req := httptest.NewRequest("GET", "/events/news", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "id: 1\nevent: news\ndata: resume after 0\n\nretry: 3000\ndata: two\ndata: lines\n\n", w.Body.String())
+}
+
+func TestSSEEncodeNewlines(t *testing.T) {
+	ev := Event{ID: "1\ndata: x", Event: "a\r\nretry: 1", Data: "one\r\ntwo\rthree"}
+	assert.Equal(t, "id: 1data: x\nevent: aretry: 1\ndata: one\ndata: two\ndata: three\n\n", string(ev.encode()))
+}
+
+// brokenWriter fails writes after the first, as if the client left.
+type brokenWriter struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *brokenWriter) Write(p []byte) (int, error) {
+	if w.writes++; w.writes > 1 {
+		return 0, errors.New("broken pipe")
+	}
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestSSEDisconnect(t *testing.T) {
+	r := &Router{}
+	r.Route("/events").SSE(func(ctx context.Context, send chan<- Event, env map[string]string) {
+		send <- Event{Data: "hello"}
+		send <- Event{Data: "lost"}
+		<-ctx.Done()
+		send <- Event{Data: "dropped"}
+	})
+
+	w := &brokenWriter{ResponseRecorder: httptest.NewRecorder()}
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	assert.Equal(t, "data: hello\n\n", w.Body.String())
+	assert.Equal(t, 2, w.writes)
+
+	type plain struct{ http.ResponseWriter }
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(plain{rec}, httptest.NewRequest("GET", "/events", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestSSEPanic(t *testing.T) {
+	r := &Router{}
+	r.Route("/events").SSE(func(ctx context.Context, send chan<- Event, env map[string]string) {
+		panic("boom")
+	})
+	assert.Panics(t, func() { get(r, "/events") })
+}
@@ -0,0 +1,225 @@
+package route
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// FileServer returns a handler serving files from the directory dir,
+// for use on a fallback route.  The file served is named by the part
+// of the path matched by the "*":
+//
+//     r.Route("/static/*").FuncE(route.FileServer("./public"))
+//
+// serves "/static/css/site.css" from "./public/css/site.css".
+//
+// Paths can't escape dir.  Requests for a directory serve its
+// index.html, if any, after redirecting to add a trailing slash;
+// directories are never listed.  Content types come from file
+// extensions, and conditional requests are handled, as by
+// http.ServeContent.
+//
+// Byte-range requests, with one range or several, get 206 Partial
+// Content responses, and all responses advertise "Accept-Ranges:
+// bytes", so large files and video can be streamed and resumed.
+//
+// Files with precompressed siblings, like "app.js.br" and "app.js.gz"
+// for "app.js", are served from the sibling with the appropriate
+// Content-Encoding when the client accepts it, avoiding compression at
+// request time for assets compressed at build time.
+func FileServer(dir string) HandlerE {
+	return fileServer(os.DirFS(dir))
+}
+
+// FileServerFS is like FileServer, but serves files from fsys, such as
+// an embed.FS bundling assets into the binary.  If dir is not empty,
+// the files served are those beneath dir in fsys, so that
+//
+//     //go:embed assets
+//     var assets embed.FS
+//
+//     r.Route("/static/*").FuncE(route.FileServerFS(assets, "assets"))
+//
+// serves "/static/site.css" from "assets/site.css".  It panics if dir
+// is not a valid path.
+//
+// Files without a modification time, as in an embed.FS, are served
+// with an ETag computed from their content in place of a Last-Modified
+// header, so conditional requests still work.
+func FileServerFS(fsys fs.FS, dir string) HandlerE {
+	if dir != "" && dir != "." {
+		sub, err := fs.Sub(fsys, dir)
+		if err != nil {
+			panic(err.Error())
+		}
+		fsys = sub
+	}
+	return fileServer(fsys)
+}
+
+func fileServer(fsys fs.FS) HandlerE {
+	return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		name, ok := fileName(env["*"])
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		serveFile(w, req, fsys, name)
+	}
+}
+
+// fileName converts the path captured from a URL to a name in an
+// fs.FS.  Cleaning the path as if rooted keeps ".." elements from
+// escaping.
+func fileName(p string) (string, bool) {
+	name := path.Clean("/" + p)[1:]
+	if name == "" {
+		name = "."
+	}
+	return name, fs.ValidPath(name) && !strings.Contains(name, "\\")
+}
+
+// serveFile serves the file name from fsys.
+func serveFile(w http.ResponseWriter, req *http.Request, fsys fs.FS, name string) {
+	f, st, err := openFile(fsys, name)
+	if err == nil && st.IsDir() {
+		f.Close()
+		if !strings.HasSuffix(req.URL.Path, "/") {
+			redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+		name = path.Join(name, "index.html")
+		f, st, err = openFile(fsys, name)
+		if err == nil && st.IsDir() {
+			f.Close()
+			err = fs.ErrNotExist
+		}
+	}
+	if err != nil {
+		fileError(w, req, err)
+		return
+	}
+	typeName := st.Name()
+	if enc, vary := precompressed(fsys, name, req); vary {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if enc != nil {
+			if cf, cst, err := openFile(fsys, name+precompressedExt[enc.Name]); err == nil {
+				f.Close()
+				f, st = cf, cst
+				w.Header().Set("Content-Encoding", enc.Name)
+			}
+		}
+	}
+	defer f.Close()
+	if w.Header().Get("Content-Type") == "" {
+		if ctype := mime.TypeByExtension(path.Ext(typeName)); ctype != "" {
+			w.Header().Set("Content-Type", ctype)
+		}
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			fileError(w, req, err)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+	if st.ModTime().IsZero() && w.Header().Get("ETag") == "" {
+		h := sha256.New()
+		if _, err := io.Copy(h, content); err != nil {
+			fileError(w, req, err)
+			return
+		}
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			fileError(w, req, err)
+			return
+		}
+		w.Header().Set("ETag", hashTag(h.Sum(nil)))
+	}
+	http.ServeContent(w, req, typeName, st.ModTime(), content)
+}
+
+// precompressedExt maps content codings to the extensions of the
+// precompressed files served for them.
+var precompressedExt = map[string]string{"br": ".br", "gzip": ".gz"}
+
+// precompressedEncoders lists the codings of precompressed files, most
+// preferred first.
+var precompressedEncoders = []Encoder{{Name: "br"}, {Name: "gzip"}}
+
+// precompressed looks for precompressed versions of the file name in
+// fsys, like "app.js.br" and "app.js.gz" for "app.js".  It reports
+// whether there are any, so the response varies by Accept-Encoding,
+// and which coding, if any, the request prefers.
+func precompressed(fsys fs.FS, name string, req *http.Request) (*Encoder, bool) {
+	var offers []Encoder
+	for _, e := range precompressedEncoders {
+		if st, err := fs.Stat(fsys, name+precompressedExt[e.Name]); err == nil && !st.IsDir() {
+			offers = append(offers, e)
+		}
+	}
+	if len(offers) == 0 {
+		return nil, false
+	}
+	return chooseEncoding(req.Header.Get("Accept-Encoding"), offers), true
+}
+
+func openFile(fsys fs.FS, name string) (fs.File, fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, st, nil
+}
+
+// fileError responds to a failure to open or read a file.
+func fileError(w http.ResponseWriter, req *http.Request, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.NotFound(w, req)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// SPA returns a handler for a single-page app, for use on a fallback
+// route.  Like FileServerFS it serves the files in fsys, but requests
+// for paths with no file behind them, which the app routes on the
+// client, get the file index instead:
+//
+//     //go:embed dist
+//     var dist embed.FS
+//
+//     app, _ := fs.Sub(dist, "dist")
+//     r.Route("/*").FuncE(route.SPA(app, "index.html"))
+//
+// The index, whether requested directly or as the fallback, is sent
+// with "Cache-Control: no-cache", so clients pick up new deployments.
+func SPA(fsys fs.FS, index string) HandlerE {
+	return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		if name, ok := fileName(env["*"]); ok && name != "." && name != index {
+			if st, err := fs.Stat(fsys, name); err == nil && !st.IsDir() {
+				serveFile(w, req, fsys, name)
+				return
+			}
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+		serveFile(w, req, fsys, index)
+	}
+}
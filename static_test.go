@@ -0,0 +1,159 @@
+package route
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFiles creates the given files beneath a temporary directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0777))
+		assert.Nil(t, os.WriteFile(p, []byte(content), 0666))
+	}
+	return dir
+}
+
+func TestFileServer(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"css/site.css":    "body {}",
+		"docs/index.html": "<p>docs</p>",
+		"empty/x.txt":     "x",
+	})
+	os.WriteFile(filepath.Join(filepath.Dir(dir), "secret"), []byte("secret"), 0666)
+	r := &Router{}
+	r.Route("/static/*").FuncE(FileServer(dir))
+
+	w := get(r, "/static/css/site.css")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "body {}", w.Body.String())
+	assert.Equal(t, "text/css; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = get(r, "/static/docs")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/static/docs/", w.Header().Get("Location"))
+	assert.Equal(t, "<p>docs</p>", get(r, "/static/docs/").Body.String())
+
+	assert.Equal(t, http.StatusNotFound, get(r, "/static/empty/").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/static/missing.css").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/static/../secret").Code)
+	assert.Equal(t, http.StatusNotFound, get(r, "/static/%2e%2e/secret").Code)
+
+	st, _ := os.Stat(filepath.Join(dir, "css/site.css"))
+	w = conditional(r, "GET", "/static/css/site.css",
+		"If-Modified-Since", st.ModTime().Add(time.Second).UTC().Format(http.TimeFormat))
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestFileServerFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/site.css":    {Data: []byte("body {}")},
+		"assets/index.html":  {Data: []byte("<p>home</p>")},
+		"assets/dated.txt":   {Data: []byte("dated"), ModTime: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"private/secret.txt": {Data: []byte("secret")},
+	}
+	r := &Router{}
+	r.Route("/static/*").FuncE(FileServerFS(fsys, "assets"))
+
+	w := get(r, "/static/site.css")
+	assert.Equal(t, "body {}", w.Body.String())
+	assert.Equal(t, "", w.Header().Get("Last-Modified"))
+	tag := w.Header().Get("ETag")
+	assert.NotEqual(t, "", tag)
+	assert.Equal(t, http.StatusNotModified, conditional(r, "GET", "/static/site.css", "If-None-Match", tag).Code)
+
+	w = get(r, "/static/dated.txt")
+	assert.Equal(t, "", w.Header().Get("ETag"))
+	assert.Equal(t, "Thu, 01 Jan 2026 00:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	assert.Equal(t, "<p>home</p>", get(r, "/static/").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/static/../private/secret.txt").Code)
+
+	assert.Panics(t, func() { FileServerFS(fsys, "../x") })
+}
+
+func TestSPA(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":    {Data: []byte("<app>")},
+		"assets/app.js": {Data: []byte("app()")},
+	}
+	r := &Router{}
+	r.Route("/api/users").FuncE(F1)
+	r.Route("/*").FuncE(SPA(fsys, "index.html"))
+
+	w := get(r, "/assets/app.js")
+	assert.Equal(t, "app()", w.Body.String())
+	assert.Equal(t, "", w.Header().Get("Cache-Control"))
+
+	// A direct request for the index gets it too, not as a plain file.
+	for _, path := range []string{"/", "/index.html", "/users/5", "/assets", "/assets/missing.js", "/../index.html"} {
+		w = get(r, path)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "<app>", w.Body.String(), path)
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"), path)
+	}
+	assert.NotNil(t, r.lookupPath("/api/users", nil))
+}
+
+func TestFileServerPrecompressed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.js":    {Data: []byte("app()")},
+		"app.js.gz": {Data: []byte("gzipped")},
+		"app.js.br": {Data: []byte("brotli")},
+		"plain.js":  {Data: []byte("plain()")},
+	}
+	r := &Router{}
+	r.Route("/*").FuncE(FileServerFS(fsys, ""))
+
+	w := withHeader(r, "/app.js", "Accept-Encoding", "gzip, br")
+	assert.Equal(t, "brotli", w.Body.String())
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "text/javascript; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+	w = withHeader(r, "/app.js", "Accept-Encoding", "gzip")
+	assert.Equal(t, "gzipped", w.Body.String())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	w = withHeader(r, "/app.js", "", "")
+	assert.Equal(t, "app()", w.Body.String())
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+	w = withHeader(r, "/plain.js", "Accept-Encoding", "gzip")
+	assert.Equal(t, "plain()", w.Body.String())
+	assert.Equal(t, "", w.Header().Get("Vary"))
+}
+
+func TestFileServerRange(t *testing.T) {
+	fsys := fstest.MapFS{"video.mp4": {Data: []byte("0123456789")}}
+	r := &Router{}
+	r.Use(Compress{}.Middleware())
+	r.Route("/*").FuncE(FileServerFS(fsys, ""))
+
+	w := get(r, "/video.mp4")
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+
+	w = conditional(r, "GET", "/video.mp4", "Range", "bytes=2-4", "Accept-Encoding", "gzip")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 2-4/10", w.Header().Get("Content-Range"))
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "234", w.Body.String())
+
+	w = conditional(r, "GET", "/video.mp4", "Range", "bytes=0-1,8-")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Regexp(t, "^multipart/byteranges; boundary=", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "Content-Range: bytes 0-1/10\r\n")
+	assert.Contains(t, w.Body.String(), "Content-Range: bytes 8-9/10\r\n")
+
+	w = conditional(r, "GET", "/video.mp4", "Range", "bytes=20-")
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+}
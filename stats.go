@@ -0,0 +1,56 @@
+package route
+
+import "time"
+
+// RouteStats is the traffic seen by a route; see Stats.
+type RouteStats struct {
+	// Pattern is the full pattern of the route, e.g. "/users/:id", as
+	// in RouteInfo.
+	Pattern string
+	// Hits is the number of requests the route has matched, and
+	// LastHit the time of the latest, or zero if there have been none.
+	Hits    int64
+	LastHit time.Time
+}
+
+// hit records a request matching r.
+func (r *Router) hit() {
+	r.hits.Add(1)
+	r.lastHit.Store(timeNow().UnixNano())
+}
+
+// stats returns the traffic seen by r.
+func (r *Router) stats() RouteStats {
+	s := RouteStats{Pattern: r.pattern, Hits: r.hits.Load()}
+	if t := r.lastHit.Load(); t != 0 {
+		s.LastHit = time.Unix(0, t)
+	}
+	return s
+}
+
+// Stats returns the traffic seen by each route beneath r, in the order
+// visited by Walk, so operators can find dead routes and hot paths.
+// Requests are only counted while TrackStats is set on the router
+// serving them:
+//
+//     r.TrackStats = true
+//     ...
+//     for _, s := range r.Stats() {
+//         if s.Hits == 0 {
+//             log.Printf("unused route %s", s.Pattern)
+//         }
+//     }
+//
+// Routes with variants (see Header) are counted as one.  The counts
+// are also shown by DebugHandler.
+func (r *Router) Stats() []RouteStats {
+	defer r.rlock()()
+	var stats []RouteStats
+	r.walk(func(n *Router) error {
+		if n.match == nil && (n.handler != nil || len(n.variants) > 0) {
+			stats = append(stats, n.stats())
+		}
+		return nil
+	})
+	return stats
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	now := fakeClock(t)
+	r := &Router{TrackStats: true}
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/hook").Header("X-Event", "push").FuncE(F1)
+	r.Route("/hook").Method("POST").FuncE(F1)
+	r.Route("/old").FuncE(F1)
+
+	get(r, "/users/1")
+	*now = now.Add(time.Minute)
+	get(r, "/users/2")
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/hook", nil))
+	get(r, "/nowhere")
+
+	stats := r.Stats()
+	assert.Equal(t, 3, len(stats))
+	assert.Equal(t, "/hook", stats[0].Pattern)
+	assert.Equal(t, int64(1), stats[0].Hits)
+	assert.Equal(t, "/old", stats[1].Pattern)
+	assert.Equal(t, int64(0), stats[1].Hits)
+	assert.True(t, stats[1].LastHit.IsZero())
+	assert.Equal(t, "/users/:id", stats[2].Pattern)
+	assert.Equal(t, int64(2), stats[2].Hits)
+	assert.True(t, stats[2].LastHit.Equal(*now))
+
+	w := get(r.DebugHandler(), "/_routes")
+	assert.True(t, strings.Contains(w.Body.String(),
+		"<td>github.com/evmar/route.F1</td><td>2</td><td>"+stats[2].LastHit.Format("2006-01-02 15:04:05")+"</td>"))
+
+	var tree struct {
+		Children map[string]struct{ Var jsonNode }
+	}
+	data, err := json.Marshal(r)
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(data, &tree))
+	assert.Equal(t, int64(2), tree.Children["users"].Var.Hits)
+	assert.Equal(t, "2026-01-01T00:01:00Z", tree.Children["users"].Var.LastHit)
+
+	// Without TrackStats nothing is counted.
+	r.TrackStats = false
+	get(r, "/old")
+	assert.Equal(t, int64(0), r.Stats()[1].Hits)
+}
@@ -0,0 +1,191 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StreamOptions configures Stream.
+type StreamOptions struct {
+	// ContentType is the response's Content-Type;
+	// "text/plain; charset=utf-8" by default.
+	ContentType string
+
+	// Buffered stops the Stream flushing after every Write, for
+	// handlers that call Flush themselves.
+	Buffered bool
+
+	// Heartbeat, if non-zero, writes HeartbeatData whenever that long
+	// passes without a Write, so proxies and load balancers don't time
+	// out a stream that is waiting for work.  Heartbeats begin once the
+	// response has, so a handler can begin it early with Flush.
+	// HeartbeatData is "\n" by default.
+	Heartbeat     time.Duration
+	HeartbeatData []byte
+}
+
+// Stream is the response writer of a streaming handler; see
+// Router.Stream.  Its methods may be called from several goroutines.
+type Stream struct {
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	o    StreamOptions
+	ctx  context.Context
+	stop context.CancelFunc
+
+	mu      sync.Mutex
+	started bool
+	wrote   bool // since the last heartbeat tick
+	err     error
+}
+
+// Header returns the response headers, which may be changed until the
+// first Write or Flush.
+func (s *Stream) Header() http.Header {
+	return s.w.Header()
+}
+
+// Context returns the stream's context, which is done once the
+// client goes away or a write to it fails.
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
+
+// Write sends p to the client, flushing it unless the stream is
+// Buffered.  It fails once the stream's context is done.
+func (s *Stream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wrote = true
+	return s.write(p, !s.o.Buffered)
+}
+
+// Flush sends any buffered data to the client.
+func (s *Stream) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.write(nil, true)
+	return err
+}
+
+// write is Write without locking.
+func (s *Stream) write(p []byte, flush bool) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.start()
+	n, err := s.w.Write(p)
+	if err == nil && flush {
+		err = s.rc.Flush()
+	}
+	if err != nil {
+		s.err = err
+		s.stop()
+	}
+	return n, err
+}
+
+// start writes the response headers, if they haven't been.
+func (s *Stream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	if s.w.Header().Get("Content-Type") == "" {
+		s.w.Header().Set("Content-Type", s.o.ContentType)
+	}
+	s.w.Header().Set("X-Accel-Buffering", "no")
+	s.w.WriteHeader(http.StatusOK)
+	if s.o.Heartbeat > 0 {
+		go s.heartbeat()
+	}
+}
+
+// heartbeat writes HeartbeatData every Heartbeat without a Write,
+// until ctx is done.
+func (s *Stream) heartbeat() {
+	t := time.NewTicker(s.o.Heartbeat)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.mu.Lock()
+			if !s.wrote {
+				s.write(s.o.HeartbeatData, true)
+			}
+			s.wrote = false
+			s.mu.Unlock()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// Stream registers f to stream a long-running response at the current
+// point, such as the progress of a job or rows of a large export:
+//
+//     r.Route("/jobs/:id/progress").Stream(func(s *route.Stream, env map[string]string) error {
+//         for p := range job(env["id"]).Progress(s.Context()) {
+//             if _, err := fmt.Fprintf(s, "%d%%\n", p); err != nil {
+//                 return err
+//             }
+//         }
+//         return nil
+//     }, route.StreamOptions{Heartbeat: 15 * time.Second})
+//
+// Writes to s are flushed to the client as they happen, and fail once
+// the client has gone away or f has returned; s's Context is done then
+// too, so f can stop work nobody is waiting for.  An error returned by
+// f before anything was written is handled by the route's error
+// handler (see ErrorHandler); afterwards, the response has begun, so
+// the error is only logged, unless it is the stream's own failure.
+// Writers that can't flush get a 500 Internal Server Error.  At most
+// one StreamOptions may be given.
+func (r *Router) Stream(f func(s *Stream, env map[string]string) error, opts ...StreamOptions) {
+	if len(opts) > 1 {
+		panic("route: Stream takes at most one StreamOptions")
+	}
+	var o StreamOptions
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+	if o.ContentType == "" {
+		o.ContentType = "text/plain; charset=utf-8"
+	}
+	if o.HeartbeatData == nil {
+		o.HeartbeatData = []byte("\n")
+	}
+	bind := func(n *Router) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			if !flushable(w) {
+				http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+				return
+			}
+			ctx, cancel := context.WithCancel(req.Context())
+			defer cancel()
+			s := &Stream{w: w, rc: http.NewResponseController(w), o: o, ctx: ctx, stop: cancel}
+			err := f(s, env)
+			cancel()
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			switch {
+			case err == nil:
+				s.start()
+			case !s.started:
+				n.handleError(w, req, err)
+			case err != s.err && !errors.Is(err, context.Canceled):
+				logf(req, "stream error: %v", err)
+			}
+		}
+	}
+	defer r.lock()()
+	if err := r.setBoundHandler(bind, funcName(f)); err != nil {
+		panic(err.Error())
+	}
+}
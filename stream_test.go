@@ -0,0 +1,69 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	r := &Router{}
+	r.Route("/count/:n").Stream(func(s *Stream, env map[string]string) error {
+		n := Env(env).MustInt("n")
+		if n < 0 {
+			return Errorf(http.StatusBadRequest, "negative count")
+		}
+		for i := 1; i <= n; i++ {
+			fmt.Fprintf(s, "%d\n", i)
+		}
+		return nil
+	})
+
+	w := get(r, "/count/3")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "1\n2\n3\n", w.Body.String())
+
+	// Errors before the response begins get the error handler.
+	w = get(r, "/count/-1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStreamDisconnect(t *testing.T) {
+	r := &Router{}
+	var errs []error
+	r.Route("/").Stream(func(s *Stream, env map[string]string) error {
+		for _, p := range []string{"one", "two", "three"} {
+			_, err := s.Write([]byte(p))
+			errs = append(errs, err)
+		}
+		return s.Context().Err()
+	})
+	w := &brokenWriter{ResponseRecorder: httptest.NewRecorder()}
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "one", w.Body.String())
+	assert.Nil(t, errs[0])
+	assert.Equal(t, "broken pipe", errs[1].Error())
+	assert.Equal(t, errs[1], errs[2])
+}
+
+func TestStreamHeartbeat(t *testing.T) {
+	r := &Router{}
+	r.Route("/").Stream(func(s *Stream, env map[string]string) error {
+		s.Header().Set("Content-Type", "application/x-ndjson")
+		s.Flush()
+		time.Sleep(50 * time.Millisecond)
+		return errors.New("late failure")
+	}, StreamOptions{Heartbeat: 10 * time.Millisecond, HeartbeatData: []byte("{}\n")})
+
+	w := get(r, "/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "{}\n")
+}
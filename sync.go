@@ -0,0 +1,148 @@
+package route
+
+import "errors"
+
+// Concurrency: a tree may be modified while it serves requests.  Each
+// tree is guarded by a read-write lock on its root node, which lookups
+// take for reading and registrations for writing.  Handlers run
+// without the lock held, so they may themselves register routes.
+// Frozen trees (see Freeze) can't change, so skip the lock entirely.
+
+// root returns the root of the tree containing r.
+func (r *Router) root() *Router {
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// lock acquires the write lock for r's tree and returns the function
+// that releases it, for use as in:
+//
+//     defer r.lock()()
+//
+// Since it precedes every change to the tree, it also puts r back in
+// the tree if Remove pruned it; see reattach.
+func (r *Router) lock() func() {
+	unlock, err := r.lockE()
+	if err != nil {
+		panic(err.Error())
+	}
+	return unlock
+}
+
+// lockE is like lock, but returns an error, holding no lock, rather
+// than panicking if r can't be put back in the tree, for methods that
+// report errors.
+func (r *Router) lockE() (func(), error) {
+	mu := &r.root().mu
+	mu.Lock()
+	if err := r.reattach(); err != nil {
+		mu.Unlock()
+		return nil, err
+	}
+	return mu.Unlock, nil
+}
+
+// rlock acquires the read lock for r's tree, unless it is frozen, and
+// returns the function that releases it.
+func (r *Router) rlock() func() {
+	root := r.root()
+	if root.frozen.Load() {
+		return func() {}
+	}
+	root.mu.RLock()
+	return root.mu.RUnlock
+}
+
+// Swap replaces the routes, handlers and variants beneath r with
+// those of other, as a single change with respect to concurrent
+// requests: each request sees either the old tree or the new one.
+// Settings on r itself are kept: options like Strict, and middleware,
+// NotFound, MethodNotAllowed, ErrorHandler, MaxBodySize and Templates.
+// Swap returns an error, changing nothing, if other's root has any
+// of those settings, since they would be lost.
+//
+// other is consumed by the swap and must not be used afterwards, nor
+// may routers for the replaced routes that callers still hold: changes
+// through them panic or, for methods returning errors, fail.
+func (r *Router) Swap(other *Router) error {
+	if other.isFrozen() {
+		return ErrFrozen
+	}
+	if len(other.middleware) > 0 || other.notFound != nil || other.methodNotAllowed != nil ||
+		other.errorHandler != nil || other.maxBody != 0 || other.templates != nil {
+		return errors.New("route: Swap: settings on other's root would be lost; set them on r")
+	}
+	unlock, err := r.lockE()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if r.isFrozen() {
+		return ErrFrozen
+	}
+	r.invalidate()
+	// Routers for the old tree that callers still hold, and other
+	// itself, must not come back into the tree; see reattach.
+	r.orphanChildren()
+	other.orphaned = true
+	r.matchers = other.matchers
+	r.varName, r.varRouter = other.varName, other.varRouter
+	r.handler, r.handlerName = other.handler, other.handlerName
+	if r.bind = other.bind; r.bind != nil {
+		r.handler = r.bind(r)
+	}
+	r.fallbackRouter = other.fallbackRouter
+	r.name, r.meta = other.name, other.meta
+	r.variants = other.variants
+	r.assets = other.assets
+	if other.bodyLimits.Load() {
+		r.root().bodyLimits.Store(true)
+	}
+	r.skip, r.skipTo, r.sortedKeys = other.skip, other.skipTo, nil
+	r.adopt()
+	return nil
+}
+
+// orphanChildren marks every node beneath r as replaced by Swap.
+func (r *Router) orphanChildren() {
+	for _, m := range r.matchers {
+		m.walk(orphan)
+	}
+	for _, v := range r.variants {
+		v.walk(orphan)
+	}
+	if r.varRouter != nil {
+		r.varRouter.walk(orphan)
+	}
+	if r.fallbackRouter != nil {
+		r.fallbackRouter.walk(orphan)
+	}
+}
+
+func orphan(n *Router) error {
+	n.orphaned = true
+	return nil
+}
+
+// adopt fixes up the parent pointers and patterns of r's descendants
+// after they were moved beneath r.
+func (r *Router) adopt() {
+	for k, m := range r.matchers {
+		m.parent, m.pattern = r, r.pattern+"/"+k
+		m.adopt()
+	}
+	for _, v := range r.variants {
+		v.parent, v.pattern = r, r.pattern
+		v.adopt()
+	}
+	if r.varRouter != nil {
+		r.varRouter.parent, r.varRouter.pattern = r, r.pattern+"/:"+r.varName
+		r.varRouter.adopt()
+	}
+	if r.fallbackRouter != nil {
+		r.fallbackRouter.parent, r.fallbackRouter.pattern = r, r.pattern+"/*"
+		r.fallbackRouter.adopt()
+	}
+}
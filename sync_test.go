@@ -0,0 +1,83 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentRegistration(t *testing.T) {
+	r := &Router{}
+	r.Route("/hook").Header("X-Event", "push").FuncE(F1)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				r.Route(fmt.Sprintf("/g%d/r%d/:id", i, j)).FuncE(F1)
+				r.Route("/hook").Header("X-Other", fmt.Sprint(i, j)).FuncE(F1)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				get(r, "/g0/r0/5")
+				get(r, "/hook")
+				r.Routes()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 401, len(r.Routes()))
+}
+
+func TestRegisterFromHandler(t *testing.T) {
+	r := &Router{}
+	r.Route("/add").Func(func(w http.ResponseWriter, req *http.Request) {
+		r.Route("/added").Func(writeString("added"))
+	})
+	get(r, "/add")
+	assert.Equal(t, "added", get(r, "/added").Body.String())
+}
+
+func TestSwap(t *testing.T) {
+	r := &Router{}
+	r.Route("/old").Func(writeString("old"))
+	r.NotFound(writeString("custom 404"))
+	r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			w.Header().Set("X-Kept", "1")
+			next(w, req, env)
+		}
+	})
+
+	next := &Router{}
+	next.Route("/new/:id").Name("new").Func(writeString("new"))
+	assert.Nil(t, r.Swap(next))
+
+	// r's own settings survive the swap.
+	assert.Equal(t, "custom 404", get(r, "/old").Body.String())
+	assert.Equal(t, "1", get(r, "/new/5").Header().Get("X-Kept"))
+	assert.Equal(t, "new", get(r, "/new/5").Body.String())
+	u, err := r.URL("new", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "/new/5", u)
+
+	// Swapping into a subtree rebases the patterns.
+	sub := &Router{}
+	sub.Route("/x").Func(writeString("x"))
+	assert.Nil(t, r.Route("/mnt").Swap(sub))
+	assert.Equal(t, "x", get(r, "/mnt/x").Body.String())
+	assert.Equal(t, "/mnt/x", r.Routes()[0].Pattern)
+
+	// Settings on other's root would be lost, so are refused.
+	bad := &Router{}
+	bad.Route("/y").Func(writeString("y"))
+	bad.NotFound(writeString("other 404"))
+	assert.NotNil(t, r.Swap(bad))
+	assert.Equal(t, "new", get(r, "/new/5").Body.String())
+}
@@ -0,0 +1,59 @@
+package route
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// Templates sets the template set used by RenderHTML at and beneath
+// the current point, and returns the router to allow chaining.  As
+// with ErrorHandler, a set given to a subtree overrides one given
+// nearer the root, so that, say, an admin section can have its own
+// layout:
+//
+//     tmpl := template.Must(template.New("").Funcs(r.FuncMap()).ParseGlob("templates/*.html"))
+//     r.Templates(tmpl)
+//
+// The reverse-routing functions of FuncMap must be defined when tmpl
+// is parsed, as above; Templates rebinds them to the whole tree the
+// current point belongs to, so "url" finds routes named anywhere in it.
+func (r *Router) Templates(tmpl *template.Template) *Router {
+	r.mustNotBeFrozen()
+	tmpl.Funcs(r.root().FuncMap())
+	defer r.lock()()
+	r.templates = tmpl
+	return r
+}
+
+// RenderHTML executes the template called name, from the set given to
+// Templates at or above r, with data, and writes the result as an HTML
+// response:
+//
+//     site := r.Route("/site")
+//     site.Route("/about").FuncErr(func(w http.ResponseWriter, req *http.Request, env map[string]string) error {
+//         return site.RenderHTML(w, "about.html", aboutPage{})
+//     })
+//
+// The template is executed before anything is written, so if it fails
+// RenderHTML writes nothing and returns the error, for a FuncErr
+// handler to return.
+func (r *Router) RenderHTML(w http.ResponseWriter, name string, data interface{}) error {
+	unlock := r.rlock()
+	var tmpl *template.Template
+	for n := r; n != nil && tmpl == nil; n = n.parent {
+		tmpl = n.templates
+	}
+	unlock()
+	if tmpl == nil {
+		return fmt.Errorf("route: no templates for %s", r.pattern)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+	return nil
+}
@@ -0,0 +1,40 @@
+package route
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplates(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id").Name("user").FuncE(F1)
+	site := r.Route("/site")
+	admin := site.Route("/admin")
+	parse := func(text string) *template.Template {
+		return template.Must(template.New("page").Funcs(site.FuncMap()).Parse(text))
+	}
+	// Templates parsed against the subtree still build URLs for the
+	// whole tree.
+	site.Templates(parse(`<a href="{{ url "user" . }}">{{ . }}</a>`))
+	admin.Templates(parse(`admin {{ . }}`))
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, site.Route("/about").RenderHTML(w, "page", "ann"))
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<a href="/users/ann">ann</a>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, admin.Route("/users").RenderHTML(w, "page", "<b>"))
+	assert.Equal(t, "admin &lt;b&gt;", w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.NotNil(t, site.RenderHTML(w, "missing", nil))
+	assert.Equal(t, 0, w.Body.Len())
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NotNil(t, r.RenderHTML(w, "page", nil))
+}
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"io"
+	"net/http/httptest"
+)
+
+// TestRequest serves a request with the given method, path and body,
+// which may be nil, and returns the recorded response.  It saves
+// boilerplate in handler tests:
+//
+//     w := r.TestRequest("POST", "/users", strings.NewReader(`{"name":"evan"}`))
+//     if w.Code != http.StatusCreated { ... }
+//
+// The request is built with httptest.NewRequest, so path may include
+// a query string, and an invalid path panics.
+func (r *Router) TestRequest(method, path string, body io.Reader) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, body))
+	return w
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestRequest(t *testing.T) {
+	r := &Router{}
+	r.Route("/echo").Method("POST").Func(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.URL.Query().Get("q") + ":" + string(body)))
+	})
+
+	w := r.TestRequest("POST", "/echo?q=x", strings.NewReader("hi"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "x:hi", w.Body.String())
+
+	assert.Equal(t, http.StatusMethodNotAllowed, r.TestRequest("GET", "/echo", nil).Code)
+}
@@ -0,0 +1,103 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeout returns middleware limiting handlers to d.  The request's
+// context gets a deadline of d, and if the handler hasn't returned by
+// then the client gets a 504 Gateway Timeout, and the timeout is
+// logged along with the matched pattern (see Pattern).  It keeps slow
+// endpoints from holding connections indefinitely:
+//
+//     r.Route("/reports").Use(route.Timeout(30 * time.Second))
+//
+// As with http.TimeoutHandler, the handler's response is buffered
+// until it returns, and writes after the timeout fail with
+// http.ErrHandlerTimeout.  The handler should stop work when the
+// context is done.
+func Timeout(d time.Duration) Middleware {
+	return func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			ctx, cancel := context.WithTimeout(req.Context(), d)
+			defer cancel()
+			req = req.WithContext(ctx)
+			// The handler may outlive this call, and so env's reuse.
+			env = maps.Clone(env)
+
+			tw := &timeoutWriter{ctx: ctx, header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if v := recover(); v != nil {
+						panicked <- v
+					}
+				}()
+				next(tw, req, env)
+				close(done)
+			}()
+
+			select {
+			case v := <-panicked:
+				panic(v)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				copyHeader(w.Header(), tw.header)
+				if tw.code == 0 {
+					tw.code = http.StatusOK
+				}
+				w.WriteHeader(tw.code)
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				tw.timedOut = true
+				tw.mu.Unlock()
+				if ctx.Err() == context.DeadlineExceeded {
+					logf(req, "timed out after %v", d)
+					http.Error(w, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+				}
+			}
+		}
+	}
+}
+
+// timeoutWriter buffers a response for Timeout.
+type timeoutWriter struct {
+	ctx    context.Context
+	header http.Header
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.header }
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.ctx.Err() == context.DeadlineExceeded {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	r := &Router{}
+	slow := r.Route("/slow")
+	slow.Use(Timeout(10 * time.Millisecond))
+	finished := make(chan error, 1)
+	slow.Route(":id").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		if env["id"] == "fast" {
+			w.Header().Set("X-Id", env["id"])
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("done"))
+			return
+		}
+		<-r.Context().Done()
+		_, err := w.Write([]byte("late"))
+		finished <- err
+	})
+
+	w := get(r, "/slow/fast")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "fast", w.Header().Get("X-Id"))
+	assert.Equal(t, "done", w.Body.String())
+
+	w = get(r, "/slow/wait")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, http.ErrHandlerTimeout, <-finished)
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	r := &Router{}
+	r.Use(Timeout(time.Second))
+	r.Route("/").FuncE(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() { get(r, "/") })
+}
@@ -0,0 +1,221 @@
+package route
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// UploadOptions configures Upload.
+type UploadOptions struct {
+	// MaxFileSize limits the size of each file; 32MB by default.
+	// MaxFiles, if non-zero, limits the number of files per request.
+	MaxFileSize int64
+	MaxFiles    int
+
+	// AllowedTypes, if set, are the media types files may have, like
+	// "image/png" or "image/*".  A file's type is sniffed from its
+	// content, as by http.DetectContentType, rather than taken from
+	// the client.
+	AllowedTypes []string
+
+	// TempDir is the directory files are spooled to; os.TempDir() by
+	// default.
+	TempDir string
+
+	// OnFile is called for each file, once it has been spooled and
+	// checked.  f's temporary file is removed when OnFile returns, so
+	// OnFile must copy it, or rename f.Path, to keep it.
+	OnFile func(f *UploadedFile, env map[string]string) error
+
+	// Done, if set, is called once all the files have been handled,
+	// with the other form fields, to write the response.  Otherwise
+	// the response is a 204 No Content.
+	Done func(w http.ResponseWriter, r *http.Request, fields url.Values, env map[string]string) error
+}
+
+// UploadedFile is a file received by Upload.
+type UploadedFile struct {
+	// Field is the form field the file was sent as, and Filename the
+	// name the client gave it, without any directory.
+	Field, Filename string
+	// ContentType is the media type sniffed from the file's content.
+	ContentType string
+	Size        int64
+	// Path is the temporary file holding the content.
+	Path string
+}
+
+// Open opens the file's content for reading.
+func (f *UploadedFile) Open() (*os.File, error) {
+	return os.Open(f.Path)
+}
+
+// maxUploadFields limits the total size of the non-file fields of an
+// upload.
+const maxUploadFields = 1 << 20
+
+// Upload registers a handler for multipart/form-data uploads at the
+// POST variant of the current point, which reads the request part by
+// part, spooling each file to disk and handing it to o.OnFile, so
+// large uploads never sit in memory:
+//
+//     r.Route("/avatars/:user").Upload(route.UploadOptions{
+//         MaxFileSize:  2 << 20,
+//         MaxFiles:     1,
+//         AllowedTypes: []string{"image/png", "image/jpeg"},
+//         OnFile: func(f *route.UploadedFile, env map[string]string) error {
+//             return os.Rename(f.Path, avatarPath(env["user"]))
+//         },
+//     })
+//
+// Violations are reported through the route's error handler (see
+// ErrorHandler) as HTTPErrors: 415 Unsupported Media Type for a
+// request that isn't multipart/form-data or a file of a type not
+// allowed, 413 Request Entity Too Large for a file or fields over
+// their limits, and 400 Bad Request for malformed requests or too
+// many files.  So are errors returned by OnFile and Done; processing
+// stops at the first error.
+func (r *Router) Upload(o UploadOptions) {
+	if o.OnFile == nil {
+		panic("route: Upload needs OnFile")
+	}
+	if o.MaxFileSize == 0 {
+		o.MaxFileSize = 32 << 20
+	}
+	v := r.Method(http.MethodPost)
+	h := func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		fields, err := o.receive(req, env)
+		if err == nil {
+			if o.Done != nil {
+				err = o.Done(w, req, fields, env)
+			} else {
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}
+		if err != nil {
+			v.handleError(w, req, err)
+		}
+	}
+	defer v.lock()()
+	if err := v.setHandler(h, funcName(o.OnFile)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// receive reads the parts of an upload, returning its non-file fields.
+func (o *UploadOptions) receive(req *http.Request, env map[string]string) (url.Values, error) {
+	mr, err := req.MultipartReader()
+	if err == http.ErrNotMultipart {
+		return nil, Errorf(http.StatusUnsupportedMediaType, "expected multipart/form-data")
+	} else if err != nil {
+		return nil, Errorf(http.StatusBadRequest, "bad upload: %w", err)
+	}
+	fields := url.Values{}
+	fieldBytes := int64(0)
+	files := 0
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return fields, nil
+		}
+		if err != nil {
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				return nil, err
+			}
+			return nil, Errorf(http.StatusBadRequest, "bad upload: %w", err)
+		}
+		if part.FileName() == "" {
+			var b strings.Builder
+			n, err := io.Copy(&b, io.LimitReader(part, maxUploadFields-fieldBytes+1))
+			if err != nil {
+				return nil, Errorf(http.StatusBadRequest, "bad upload: %w", err)
+			}
+			if fieldBytes += n; fieldBytes > maxUploadFields {
+				return nil, Errorf(http.StatusRequestEntityTooLarge, "form fields too large")
+			}
+			fields.Add(part.FormName(), b.String())
+			continue
+		}
+		if files++; o.MaxFiles > 0 && files > o.MaxFiles {
+			return nil, Errorf(http.StatusBadRequest, "too many files; at most %d allowed", o.MaxFiles)
+		}
+		if err := o.receiveFile(part, env); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// receiveFile spools a file part to disk and passes it to OnFile.
+func (o *UploadOptions) receiveFile(part *multipart.Part, env map[string]string) error {
+	tmp, err := os.CreateTemp(o.TempDir, "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	var head bytes.Buffer
+	n, err := io.Copy(io.MultiWriter(tmp, &limitedBuffer{&head, 512}), io.LimitReader(part, o.MaxFileSize+1))
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return err
+		}
+		return Errorf(http.StatusBadRequest, "bad upload: %w", err)
+	}
+	if n > o.MaxFileSize {
+		return Errorf(http.StatusRequestEntityTooLarge, "file %q too large; at most %d bytes allowed", part.FileName(), o.MaxFileSize)
+	}
+	f := &UploadedFile{
+		Field:       part.FormName(),
+		Filename:    part.FileName(),
+		ContentType: http.DetectContentType(head.Bytes()),
+		Size:        n,
+		Path:        tmp.Name(),
+	}
+	if !o.allowed(f.ContentType) {
+		return Errorf(http.StatusUnsupportedMediaType, "file %q has type %s, which is not allowed", f.Filename, f.ContentType)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return o.OnFile(f, env)
+}
+
+// allowed reports whether AllowedTypes permits contentType.
+func (o *UploadOptions) allowed(contentType string) bool {
+	if len(o.AllowedTypes) == 0 {
+		return true
+	}
+	mt, _, _ := mime.ParseMediaType(contentType)
+	typ, subtype, _ := strings.Cut(mt, "/")
+	for _, a := range o.AllowedTypes {
+		at, as, _ := strings.Cut(strings.ToLower(a), "/")
+		if at == typ && (as == "*" || as == subtype) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedBuffer keeps the first n bytes written to it, discarding the
+// rest.
+type limitedBuffer struct {
+	buf *bytes.Buffer
+	n   int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if rest := b.n - b.buf.Len(); rest > 0 {
+		b.buf.Write(p[:min(rest, len(p))])
+	}
+	return len(p), nil
+}
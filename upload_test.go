@@ -0,0 +1,100 @@
+package route
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// multipartRequest returns an upload of the given files, keyed by
+// name, along with a "title" field.
+func multipartRequest(files map[string]string) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "holiday")
+	for name, content := range files {
+		fw, _ := mw.CreateFormFile("file", name)
+		fw.Write([]byte(content))
+	}
+	mw.Close()
+	req := httptest.NewRequest("POST", "/upload/ann", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestUpload(t *testing.T) {
+	var got []string
+	var paths []string
+	r := &Router{}
+	r.Route("/upload/:user").Upload(UploadOptions{
+		MaxFileSize:  16,
+		MaxFiles:     2,
+		AllowedTypes: []string{"text/*"},
+		TempDir:      t.TempDir(),
+		OnFile: func(f *UploadedFile, env map[string]string) error {
+			rf, err := f.Open()
+			if err != nil {
+				return err
+			}
+			defer rf.Close()
+			b, _ := io.ReadAll(rf)
+			got = append(got, fmt.Sprintf("%s %s %s %s %d %s", env["user"], f.Field, f.Filename, f.ContentType, f.Size, b))
+			paths = append(paths, f.Path)
+			return nil
+		},
+		Done: func(w http.ResponseWriter, req *http.Request, fields url.Values, env map[string]string) error {
+			fmt.Fprintf(w, "saved %s", fields.Get("title"))
+			return nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, multipartRequest(map[string]string{"a.txt": "hello"}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "saved holiday", w.Body.String())
+	assert.Equal(t, []string{"ann file a.txt text/plain; charset=utf-8 5 hello"}, got)
+	// Temporary files are removed.
+	_, err := os.Stat(paths[0])
+	assert.True(t, os.IsNotExist(err))
+
+	for _, c := range []struct {
+		files  map[string]string
+		status int
+	}{
+		{map[string]string{"big.txt": strings.Repeat("x", 17)}, http.StatusRequestEntityTooLarge},
+		{map[string]string{"a.png": "\x89PNG\r\n\x1a\n"}, http.StatusUnsupportedMediaType},
+		{map[string]string{"a": "1", "b": "2", "c": "3"}, http.StatusBadRequest},
+	} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, multipartRequest(c.files))
+		assert.Equal(t, c.status, w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/upload/ann", strings.NewReader("x")))
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/upload/ann", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestUploadDefaultResponse(t *testing.T) {
+	r := &Router{}
+	r.Route("/upload/:user").Upload(UploadOptions{
+		OnFile: func(f *UploadedFile, env map[string]string) error { return nil },
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, multipartRequest(map[string]string{"a.bin": "\x00\x01"}))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Panics(t, func() { r.Route("/other").Upload(UploadOptions{}) })
+}
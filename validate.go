@@ -0,0 +1,33 @@
+package route
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Validate reports whether path is a well-formed request path: it
+// must start with "/", be valid UTF-8 and contain no control
+// characters.
+//
+// The router handles any path without panicking, and paths not
+// starting with "/" match nothing (ServeHTTP answers them with 400 Bad
+// Request), so calling Validate is not required.  It is for programs
+// that want to reject other malformed paths before routing, say in
+// middleware wrapping ServeHTTP.
+func Validate(path string) error {
+	if path == "" {
+		return fmt.Errorf("route: empty path")
+	}
+	if path[0] != '/' {
+		return fmt.Errorf("route: path %q does not start with /", path)
+	}
+	if !utf8.ValidString(path) {
+		return fmt.Errorf("route: path %q is not valid UTF-8", path)
+	}
+	for i := 0; i < len(path); i++ {
+		if c := path[i]; c < 0x20 || c == 0x7f {
+			return fmt.Errorf("route: path %q contains a control character", path)
+		}
+	}
+	return nil
+}
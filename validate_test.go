@@ -0,0 +1,32 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate("/"))
+	assert.Nil(t, Validate("/users/ünïcode"))
+	assert.NotNil(t, Validate(""))
+	assert.NotNil(t, Validate("users"))
+	assert.NotNil(t, Validate("/\xff"))
+	assert.NotNil(t, Validate("/a\x00b"))
+}
+
+func TestBadPath(t *testing.T) {
+	r := &Router{}
+	r.Route("/").FuncE(F1)
+	assert.Nil(t, r.lookupPath("", nil))
+	assert.Nil(t, r.lookupPath("x", nil))
+
+	for _, path := range []string{"", "*"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, &http.Request{Method: "OPTIONS", URL: &url.URL{Path: path}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
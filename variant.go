@@ -0,0 +1,162 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+)
+
+// variant returns the variant of r for the condition described by
+// cond, creating it with the given match function if necessary.
+func (r *Router) variant(cond string, match func(req *http.Request) bool) *Router {
+	for _, v := range r.variants {
+		if v.conds[len(v.conds)-1] == cond {
+			return v
+		}
+	}
+	r.invalidate()
+	v := &Router{
+		pattern: r.pattern,
+		Strict:  r.Strict,
+		parent:  r,
+		match:   match,
+		conds:   append(append([]string(nil), r.conds...), cond),
+		method:  r.method,
+	}
+	r.variants = append(r.variants, v)
+	return v
+}
+
+// Header returns a variant of the current point that additionally
+// requires the request header key to have the given value, or, if
+// value is empty, merely to be present.  Register a handler on the
+// variant as usual:
+//
+//     hook := r.Route("/webhook")
+//     hook.Header("X-GitHub-Event", "push").FuncE(onPush)
+//     hook.Header("X-GitHub-Event", "issues").FuncE(onIssues)
+//     hook.FuncE(onOtherEvent)
+//
+// Variants are tried in the order they were first created; requests
+// matching none of them fall through to the handler registered on the
+// path itself, or get a 404 if there is none.  Chaining conditions, as
+// in r.Header(a, x).Header(b, y), requires all of them.  Responses from
+// the path list the headers its variants read in Vary, for caches.
+func (r *Router) Header(key, value string) *Router {
+	defer r.lock()()
+	key = http.CanonicalHeaderKey(key)
+	cond := key + ": " + value
+	if value == "" {
+		cond = key
+	}
+	v := r.variant(cond, func(req *http.Request) bool {
+		vals := req.Header[key]
+		if value == "" {
+			return len(vals) > 0
+		}
+		for _, v := range vals {
+			if strings.TrimSpace(v) == value {
+				return true
+			}
+		}
+		return false
+	})
+	v.vary = key
+	return v
+}
+
+// nodeHandler returns the handler for requests matching r exactly,
+// taking its variants into account, or nil if there is none.
+func (r *Router) nodeHandler() HandlerE {
+	if len(r.variants) == 0 {
+		return r.handler
+	}
+	return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		unlock := r.rlock()
+		vary := r.varyHeaders(nil)
+		h, _, status := r.selectHandler(req)
+		var fail http.HandlerFunc
+		switch {
+		case h != nil:
+		case status == http.StatusNotFound:
+			fail = r.notFoundHandler()
+		case status == http.StatusMethodNotAllowed:
+			fail = r.methodNotAllowedHandler(r.allowedMethods())
+		}
+		unlock()
+		for _, k := range vary {
+			w.Header().Add("Vary", k)
+		}
+		if fail != nil {
+			fail(w, req)
+			return
+		}
+		if h == nil {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		h(w, req, env)
+	}
+}
+
+// varyHeaders appends to vary the request headers read by the
+// conditions of r's variants, without duplicates.
+func (r *Router) varyHeaders(vary []string) []string {
+	for _, v := range r.variants {
+		if v.vary != "" {
+			found := false
+			for _, k := range vary {
+				found = found || k == v.vary
+			}
+			if !found {
+				vary = append(vary, v.vary)
+			}
+		}
+		vary = v.varyHeaders(vary)
+	}
+	return vary
+}
+
+// selectHandler chooses among r's variants and its own handler for
+// req, returning the handler and the node it was registered on.  If
+// there is no suitable handler it returns the HTTP status to fail with
+// instead.
+func (r *Router) selectHandler(req *http.Request) (HandlerE, *Router, int) {
+	status := http.StatusNotFound
+	var accepts []*Router
+	for _, v := range r.variants {
+		if v.accept != "" {
+			accepts = append(accepts, v)
+			continue
+		}
+		if !v.match(req) {
+			if v.mismatch > status {
+				status = v.mismatch
+			}
+			continue
+		}
+		h, n, s := v.selectHandler(req)
+		if h != nil {
+			return v.wrap(h), n, 0
+		}
+		status = s
+	}
+	if len(accepts) > 0 {
+		offers := make([]string, len(accepts))
+		for i, v := range accepts {
+			offers[i] = v.accept
+		}
+		if i := negotiate(req.Header.Get("Accept"), offers); i >= 0 {
+			h, n, s := accepts[i].selectHandler(req)
+			if h != nil {
+				return accepts[i].wrap(h), n, 0
+			}
+			status = s
+		} else {
+			status = http.StatusNotAcceptable
+		}
+	}
+	if r.handler != nil {
+		return r.handler, r, 0
+	}
+	return nil, nil, status
+}
@@ -0,0 +1,72 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withHeader(h http.Handler, path, key, value string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", path, nil)
+	if key != "" {
+		req.Header.Set(key, value)
+	}
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestHeader(t *testing.T) {
+	r := &Router{}
+	hook := r.Route("/webhook")
+	hook.Header("x-github-event", "push").Func(writeString("push"))
+	hook.Header("X-GitHub-Event", "issues").Func(writeString("issues"))
+
+	assert.Equal(t, "push", withHeader(r, "/webhook", "X-GitHub-Event", "push").Body.String())
+	assert.Equal(t, "issues", withHeader(r, "/webhook", "X-GitHub-Event", "issues").Body.String())
+	assert.Equal(t, http.StatusNotFound, withHeader(r, "/webhook", "X-GitHub-Event", "star").Code)
+	assert.Equal(t, http.StatusNotFound, withHeader(r, "/webhook", "", "").Code)
+
+	hook.Func(writeString("other"))
+	assert.Equal(t, "other", withHeader(r, "/webhook", "X-GitHub-Event", "star").Body.String())
+	w := withHeader(r, "/webhook", "X-GitHub-Event", "push")
+	assert.Equal(t, "push", w.Body.String())
+	assert.Equal(t, []string{"X-Github-Event"}, w.Header().Values("Vary"))
+}
+
+func TestHeaderChained(t *testing.T) {
+	r := &Router{}
+	v := r.Route("/x").Header("A", "").Header("B", "1")
+	assert.Equal(t, []string{"A", "B: 1"}, v.conds)
+	v.Func(writeString("both"))
+	assert.True(t, v == r.Route("/x").Header("A", "").Header("B", "1"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/x", nil)
+	req.Header.Set("A", "anything")
+	req.Header.Set("B", "1")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "both", w.Body.String())
+	assert.Equal(t, []string{"A", "B"}, w.Header().Values("Vary"))
+	assert.Equal(t, http.StatusNotFound, withHeader(r, "/x", "A", "z").Code)
+
+	routes := r.Routes()
+	assert.Equal(t, 1, len(routes))
+	assert.Equal(t, []string{"A", "B: 1"}, routes[0].Conditions)
+}
+
+func TestHeaderDump(t *testing.T) {
+	r := &Router{}
+	r.Route("/hook").Header("X-Event", "push").FuncE(F1)
+	assert.Equal(t, `hook/
+  [X-Event: push]
+    => github.com/evmar/route.F1
+`, r.DumpString())
+
+	other := &Router{}
+	assert.Nil(t, other.Merge(r, ""))
+	assert.Equal(t, r.DumpString(), other.DumpString())
+	assert.NotNil(t, other.Merge(r, ""))
+}
@@ -0,0 +1,138 @@
+package route
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Versions is a set of API version prefixes sharing a set of routes;
+// see Router.Versions.
+type Versions struct {
+	prefixes []string
+	routers  []*Router
+}
+
+// Versions returns a set of version prefixes beneath the current
+// point, like "/v1" and "/v2", so routes can be registered once and
+// served under each:
+//
+//     vs := r.Versions("/v1", "/v2")
+//     vs.Each(func(version string, v *route.Router) {
+//         v.Route("/users/:id").FuncE(showUser)
+//         if version == "/v2" {
+//             v.Route("/users/:id/avatar").FuncE(showAvatar)
+//         }
+//     })
+//     vs.Version("/v2").Route("/users/:id").ReplaceFuncE(showUserV2)
+//     vs.Deprecate("/v1", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+func (r *Router) Versions(prefixes ...string) *Versions {
+	vs := &Versions{prefixes: prefixes}
+	for _, p := range prefixes {
+		vs.routers = append(vs.routers, r.Route(p))
+	}
+	return vs
+}
+
+// Each calls fn with each version's prefix and router, in the order
+// they were passed to Versions.
+func (vs *Versions) Each(fn func(version string, r *Router)) {
+	for i, p := range vs.prefixes {
+		fn(p, vs.routers[i])
+	}
+}
+
+// Version returns the router for one version, for registering routes
+// or overrides specific to it.  It returns nil for an unknown version.
+func (vs *Versions) Version(version string) *Router {
+	for i, p := range vs.prefixes {
+		if p == version {
+			return vs.routers[i]
+		}
+	}
+	return nil
+}
+
+// Deprecate marks a version as deprecated since the given time, so
+// its responses carry a Deprecation header (RFC 9745) and, if sunset
+// is non-zero, a Sunset header (RFC 8594) giving when it will be
+// removed.  It returns false for an unknown version.
+func (vs *Versions) Deprecate(version string, since, sunset time.Time) bool {
+	v := vs.Version(version)
+	if v == nil {
+		return false
+	}
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	v.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			w.Header().Set("Deprecation", deprecation)
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			next(w, req, env)
+		}
+	})
+	return true
+}
+
+// VersionSwitch dispatches requests among sub-routers by the API
+// version named in a request header; see Router.VersionHeader.
+type VersionSwitch struct {
+	header   string
+	mu       sync.RWMutex
+	versions map[string]http.Handler
+	fallback http.Handler
+}
+
+// VersionHeader dispatches every path at or beneath the current point
+// to a sub-router chosen by the value of the given request header, for
+// APIs that don't put the version in the path:
+//
+//     vs := r.Route("/api").VersionHeader("Accept-Version")
+//     vs.Version("1", v1).Version("2", v2)
+//     vs.Default(v2)
+//
+// As with MountStripPrefix, sub-routers see paths relative to the
+// current point.  Requests naming an unknown version, or none when
+// there is no default, get a 400 Bad Request.  Responses carry a Vary
+// header naming the version header.
+func (r *Router) VersionHeader(header string) *VersionSwitch {
+	vs := &VersionSwitch{header: http.CanonicalHeaderKey(header), versions: map[string]http.Handler{}}
+	r.MountStripPrefix(vs)
+	return vs
+}
+
+// Version sets the handler, usually a *Router, for requests whose
+// version header is version.  It returns vs, to allow chaining.
+func (vs *VersionSwitch) Version(version string, h http.Handler) *VersionSwitch {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	vs.versions[version] = h
+	return vs
+}
+
+// Default sets the handler for requests without the version header.
+func (vs *VersionSwitch) Default(h http.Handler) *VersionSwitch {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	vs.fallback = h
+	return vs
+}
+
+func (vs *VersionSwitch) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Add("Vary", vs.header)
+	version := strings.TrimSpace(req.Header.Get(vs.header))
+	vs.mu.RLock()
+	h := vs.fallback
+	if version != "" {
+		h = vs.versions[version]
+	}
+	vs.mu.RUnlock()
+	if h == nil {
+		http.Error(w, "unsupported API version", http.StatusBadRequest)
+		return
+	}
+	h.ServeHTTP(w, req)
+}
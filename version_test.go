@@ -0,0 +1,59 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions(t *testing.T) {
+	r := &Router{}
+	vs := r.Versions("/v1", "/v2")
+	vs.Each(func(version string, v *Router) {
+		v.Route("/users/:id").FuncE(writeEnv(version + " user "))
+		if version == "/v2" {
+			v.Route("/users/:id/avatar").FuncE(writeEnv("avatar "))
+		}
+	})
+	vs.Version("/v2").Route("/users/:id").ReplaceFuncE(writeEnv("new user "))
+	assert.Nil(t, vs.Version("/v3"))
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, vs.Deprecate("/v1", since, sunset))
+	assert.False(t, vs.Deprecate("/v3", since, sunset))
+
+	w := get(r, "/v1/users/5")
+	assert.Equal(t, "/v1 user 5", w.Body.String())
+	assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = get(r, "/v2/users/5")
+	assert.Equal(t, "new user 5", w.Body.String())
+	assert.Equal(t, "", w.Header().Get("Deprecation"))
+	assert.Equal(t, "avatar 5", get(r, "/v2/users/5/avatar").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/v1/users/5/avatar").Code)
+}
+
+func TestVersionHeader(t *testing.T) {
+	v1, v2 := &Router{}, &Router{}
+	v1.Route("/users/:id").FuncE(writeEnv("v1 "))
+	v2.Route("/users/:id").FuncE(writeEnv("v2 "))
+
+	r := &Router{}
+	vs := r.Route("/api").VersionHeader("X-API-Version")
+	vs.Version("1", v1).Version("2", v2)
+
+	w := withHeader(r, "/api/users/5", "X-API-Version", "1")
+	assert.Equal(t, "v1 5", w.Body.String())
+	assert.Equal(t, "X-Api-Version", w.Header().Get("Vary"))
+	assert.Equal(t, "v2 5", withHeader(r, "/api/users/5", "X-API-Version", " 2 ").Body.String())
+	assert.Equal(t, http.StatusBadRequest, withHeader(r, "/api/users/5", "X-API-Version", "3").Code)
+	assert.Equal(t, http.StatusBadRequest, get(r, "/api/users/5").Code)
+
+	vs.Default(v2)
+	assert.Equal(t, "v2 5", get(r, "/api/users/5").Body.String())
+	assert.Equal(t, http.StatusNotFound, get(r, "/api/other").Code)
+}
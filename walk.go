@@ -0,0 +1,129 @@
+package route
+
+import (
+	"sort"
+	"strings"
+)
+
+// WalkFunc is the type of the function called by Walk for each route.
+//
+// pattern is the full pattern of the route, as it would be passed to
+// Route, e.g. "/users/:id".  methods lists the HTTP methods the
+// handler is restricted to, or is nil if it accepts any method.
+type WalkFunc func(pattern string, methods []string, handler HandlerE) error
+
+// Walk calls f for every registered route beneath r.
+//
+// Routes are visited in a deterministic order: a node's own handler
+// first, then its variants (see Header) in registration order, then its
+// static children sorted by name, then its variable child, then its
+// fallback.  If f returns an error, Walk stops and
+// returns that error.
+//
+// The tree is locked against modification during the walk, so f must
+// not modify the router.
+func (r *Router) Walk(f WalkFunc) error {
+	defer r.rlock()()
+	return r.walk(func(n *Router) error {
+		if n.handler == nil {
+			return nil
+		}
+		return f(n.pattern, n.methods(), n.handler)
+	})
+}
+
+// walk calls f on every node beneath r, including r itself, in the
+// order documented on Walk.
+func (r *Router) walk(f func(n *Router) error) error {
+	if err := f(r); err != nil {
+		return err
+	}
+	for _, v := range r.variants {
+		if err := v.walk(f); err != nil {
+			return err
+		}
+	}
+	for _, k := range r.staticKeys() {
+		if err := r.matchers[k].walk(f); err != nil {
+			return err
+		}
+	}
+	if r.varRouter != nil {
+		if err := r.varRouter.walk(f); err != nil {
+			return err
+		}
+	}
+	if r.fallbackRouter != nil {
+		return r.fallbackRouter.walk(f)
+	}
+	return nil
+}
+
+// staticKeys returns the keys of r.matchers in sorted order.
+func (r *Router) staticKeys() []string {
+	if r.sortedKeys != nil {
+		return r.sortedKeys
+	}
+	keys := make([]string, 0, len(r.matchers))
+	for k := range r.matchers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RouteInfo describes a single registered route, as returned by Routes.
+type RouteInfo struct {
+	// Pattern is the full pattern of the route, e.g. "/users/:id".
+	Pattern string
+	// Vars lists the names of the variables captured by the route,
+	// in path order.
+	Vars []string
+	// Fallback is true if the route ends in a "*" component.
+	Fallback bool
+	// Conditions describes any additional requirements on the request
+	// for the route to match, like "X-GitHub-Event: push"; see Header.
+	Conditions []string
+	// Methods lists the HTTP methods the handler is restricted to,
+	// or is nil if it accepts any method.
+	Methods []string
+	// Name is the name given to the route with Name, if any.
+	Name string
+	// Meta is the route's metadata; see Router.Meta.
+	Meta map[string]interface{}
+	// Handler is the route's handler, and HandlerName the name of
+	// the function registered for it.
+	Handler     HandlerE
+	HandlerName string
+}
+
+// Routes returns the flattened list of routes beneath r, in the order
+// visited by Walk.
+func (r *Router) Routes() []RouteInfo {
+	defer r.rlock()()
+	var routes []RouteInfo
+	r.walk(func(n *Router) error {
+		if n.handler == nil {
+			return nil
+		}
+		info := RouteInfo{
+			Pattern:     n.pattern,
+			Methods:     n.methods(),
+			Conditions:  n.conds,
+			Name:        n.name,
+			Meta:        n.meta,
+			Handler:     n.handler,
+			HandlerName: n.handlerName,
+		}
+		for _, part := range strings.Split(n.pattern, "/") {
+			if part == "*" {
+				info.Fallback = true
+			} else if len(part) > 0 && part[0] == ':' {
+				info.Vars = append(info.Vars, part[1:])
+			}
+		}
+		routes = append(routes, info)
+		return nil
+	})
+	return routes
+}
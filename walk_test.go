@@ -0,0 +1,69 @@
+package route
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id/edit").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+	r.Route("/users/new").FuncE(F1)
+	r.Route("/").FuncE(F1)
+	r.Route("/users/:id").FuncE(F1)
+	r.Route("/about").FuncE(F1)
+
+	var patterns []string
+	err := r.Walk(func(pattern string, methods []string, h HandlerE) error {
+		assert.Nil(t, methods)
+		assert.NotNil(t, h)
+		patterns = append(patterns, pattern)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"/",
+		"/about",
+		"/static/*",
+		"/users/new",
+		"/users/:id",
+		"/users/:id/edit",
+	}, patterns)
+}
+
+func TestWalkError(t *testing.T) {
+	r := &Router{}
+	r.Route("/a").FuncE(F1)
+	r.Route("/b").FuncE(F1)
+
+	stop := errors.New("stop")
+	n := 0
+	err := r.Walk(func(pattern string, methods []string, h HandlerE) error {
+		n++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestRoutes(t *testing.T) {
+	r := &Router{}
+	r.Route("/users/:id/posts/:post").FuncE(F1)
+	r.Route("/static/*").FuncE(F1)
+	r.Route("/users") // No handler; not listed.
+
+	routes := r.Routes()
+	assert.Equal(t, 2, len(routes))
+
+	assert.Equal(t, "/static/*", routes[0].Pattern)
+	assert.True(t, routes[0].Fallback)
+	assert.Nil(t, routes[0].Vars)
+	assert.NotNil(t, routes[0].Handler)
+
+	assert.Equal(t, "/users/:id/posts/:post", routes[1].Pattern)
+	assert.False(t, routes[1].Fallback)
+	assert.Equal(t, []string{"id", "post"}, routes[1].Vars)
+}
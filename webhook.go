@@ -0,0 +1,164 @@
+package route
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookScheme is a way of signing webhook requests; see VerifyHMAC.
+type WebhookScheme interface {
+	// Verify checks the signature in header of body with secret.
+	Verify(secret []byte, header http.Header, body []byte) error
+}
+
+// WebhookSchemeFunc adapts a function to a WebhookScheme.
+type WebhookSchemeFunc func(secret []byte, header http.Header, body []byte) error
+
+func (f WebhookSchemeFunc) Verify(secret []byte, header http.Header, body []byte) error {
+	return f(secret, header, body)
+}
+
+// webhookTolerance is how old the timestamp of a signed webhook may be,
+// against replays.
+const webhookTolerance = 5 * time.Minute
+
+var (
+	// GitHubWebhook verifies GitHub's X-Hub-Signature-256 header.
+	GitHubWebhook WebhookScheme = WebhookSchemeFunc(func(secret []byte, header http.Header, body []byte) error {
+		sig, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if !ok {
+			return errors.New("missing signature")
+		}
+		return checkHMAC(secret, body, sig)
+	})
+
+	// StripeWebhook verifies Stripe's Stripe-Signature header, which
+	// must be no more than five minutes old.
+	StripeWebhook WebhookScheme = WebhookSchemeFunc(func(secret []byte, header http.Header, body []byte) error {
+		var ts string
+		var sigs []string
+		for _, kv := range strings.Split(header.Get("Stripe-Signature"), ",") {
+			k, v, _ := strings.Cut(kv, "=")
+			switch k {
+			case "t":
+				ts = v
+			case "v1":
+				sigs = append(sigs, v)
+			}
+		}
+		if ts == "" || len(sigs) == 0 {
+			return errors.New("missing signature")
+		}
+		if err := checkTimestamp(ts); err != nil {
+			return err
+		}
+		msg := append([]byte(ts+"."), body...)
+		for _, sig := range sigs {
+			if checkHMAC(secret, msg, sig) == nil {
+				return nil
+			}
+		}
+		return errors.New("invalid signature")
+	})
+
+	// SlackWebhook verifies Slack's X-Slack-Signature header, which
+	// must be no more than five minutes old.
+	SlackWebhook WebhookScheme = WebhookSchemeFunc(func(secret []byte, header http.Header, body []byte) error {
+		ts := header.Get("X-Slack-Request-Timestamp")
+		sig, ok := strings.CutPrefix(header.Get("X-Slack-Signature"), "v0=")
+		if ts == "" || !ok {
+			return errors.New("missing signature")
+		}
+		if err := checkTimestamp(ts); err != nil {
+			return err
+		}
+		return checkHMAC(secret, append([]byte("v0:"+ts+":"), body...), sig)
+	})
+)
+
+// servingNode returns the node at or beneath r serving req, as
+// recorded in req.Pattern, so that settings made on the route itself
+// are seen by middleware added further up.  It returns r if there is
+// none.
+func (r *Router) servingNode(req *http.Request) *Router {
+	rest := strings.TrimPrefix(req.Pattern, r.displayPattern())
+	if rest == "" || rest == "/" {
+		return r
+	}
+	if n := r.findPath(rest); n != nil {
+		return n
+	}
+	return r
+}
+
+// checkHMAC checks that sig is the hex HMAC-SHA256 of msg with secret.
+func checkHMAC(secret, msg []byte, sig string) error {
+	want, err := hex.DecodeString(sig)
+	if err != nil {
+		return errors.New("invalid signature")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(msg)
+	if !hmac.Equal(mac.Sum(nil), want) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// checkTimestamp checks that ts, in Unix seconds, is recent.
+func checkTimestamp(ts string) error {
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("invalid timestamp")
+	}
+	if d := timeNow().Sub(time.Unix(sec, 0)); d > webhookTolerance || d < -webhookTolerance {
+		return errors.New("timestamp too old")
+	}
+	return nil
+}
+
+// VerifyHMAC makes routes at and beneath the current point accept only
+// webhook requests signed with secret in the given scheme, and returns
+// the router to allow chaining:
+//
+//     r.Route("/hooks/github").VerifyHMAC(secret, route.GitHubWebhook).FuncE(onPush)
+//
+// The body is read in full to check its signature, limited to the
+// route's MaxBodySize or else 1MB, and then handed to the handler
+// unchanged.  Requests that fail the check get a 403 Forbidden.
+func (r *Router) VerifyHMAC(secret []byte, scheme WebhookScheme) *Router {
+	n := r
+	return r.Use(func(next HandlerE) HandlerE {
+		return func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+			unlock := n.rlock()
+			limit := n.servingNode(req).bodyLimit()
+			unlock()
+			body := req.Body
+			if body == nil {
+				body = http.NoBody
+			}
+			if limit == 0 {
+				body = http.MaxBytesReader(w, body, 1<<20)
+			}
+			b, err := io.ReadAll(body)
+			if err != nil {
+				n.handleError(w, req, err)
+				return
+			}
+			if err := scheme.Verify(secret, req.Header, b); err != nil {
+				http.Error(w, "webhook "+err.Error(), http.StatusForbidden)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(b))
+			next(w, req, env)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package route
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hexHMAC(secret, msg string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(msg))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	now := fakeClock(t)
+	secret := []byte("s3cret")
+	echo := func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		w.Write(b)
+	}
+	r := &Router{}
+	r.Route("/hooks/github").VerifyHMAC(secret, GitHubWebhook).Func(echo)
+	r.Route("/hooks/stripe").VerifyHMAC(secret, StripeWebhook).Func(echo)
+	r.Route("/hooks/slack").VerifyHMAC(secret, SlackWebhook).Func(echo)
+
+	body := `{"event":"push"}`
+	ts := strconv.FormatInt(now.Unix(), 10)
+	hook := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	github := map[string]string{"X-Hub-Signature-256": "sha256=" + hexHMAC("s3cret", body)}
+	stripe := map[string]string{"Stripe-Signature": "t=" + ts + ",v1=00,v1=" + hexHMAC("s3cret", ts+"."+body)}
+	slack := map[string]string{"X-Slack-Request-Timestamp": ts, "X-Slack-Signature": "v0=" + hexHMAC("s3cret", "v0:"+ts+":"+body)}
+	for path, h := range map[string]map[string]string{"/hooks/github": github, "/hooks/stripe": stripe, "/hooks/slack": slack} {
+		w := hook(path, h)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, body, w.Body.String(), path)
+		assert.Equal(t, http.StatusForbidden, hook(path, nil).Code, path)
+	}
+
+	w := hook("/hooks/github", map[string]string{"X-Hub-Signature-256": "sha256=" + hexHMAC("wrong", body)})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "webhook invalid signature\n", w.Body.String())
+
+	// Replays are rejected.
+	*now = now.Add(10 * time.Minute)
+	w = hook("/hooks/slack", slack)
+	assert.Equal(t, "webhook timestamp too old\n", w.Body.String())
+
+	r.Route("/hooks/small").MaxBodySize(4)
+	r.Route("/hooks/small").VerifyHMAC(secret, GitHubWebhook).Func(echo)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, hook("/hooks/small", github).Code)
+
+	// A limit on the route itself replaces the default of 1MB.
+	r.Route("/big").VerifyHMAC(secret, GitHubWebhook)
+	r.Route("/big/hook").MaxBodySize(2 << 20)
+	r.Route("/big/hook").Func(echo)
+	r.Route("/big/default").Func(echo)
+	body = strings.Repeat("x", 3<<19)
+	github = map[string]string{"X-Hub-Signature-256": "sha256=" + hexHMAC("s3cret", body)}
+	assert.Equal(t, http.StatusOK, hook("/big/hook", github).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, hook("/big/default", github).Code)
+}
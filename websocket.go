@@ -0,0 +1,57 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WebSocket registers f to handle WebSocket connections at the current
+// point.  Requests must be GET, or get a 405 Method Not Allowed, and
+// must ask to upgrade to version 13 of the WebSocket protocol, or get
+// a 426 Upgrade Required, so f only sees handshakes.  f completes the
+// handshake with a WebSocket package of choice:
+//
+//     r.Route("/rooms/:room/ws").WebSocket(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+//         conn, err := upgrader.Upgrade(w, r, nil)
+//         if err != nil {
+//             return
+//         }
+//         go chat(conn, env["room"])
+//     })
+//
+// Connections usually outlive handlers, so f is passed a private copy
+// of env, as by KeepEnv, that it may retain.
+func (r *Router) WebSocket(f func(w http.ResponseWriter, r *http.Request, env map[string]string)) {
+	v := r.Method(http.MethodGet)
+	h := KeepEnv(func(w http.ResponseWriter, req *http.Request, env map[string]string) {
+		if !hasToken(req.Header, "Connection", "upgrade") || !hasToken(req.Header, "Upgrade", "websocket") {
+			w.Header().Set("Connection", "Upgrade")
+			w.Header().Set("Upgrade", "websocket")
+			http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		if req.Header.Get("Sec-WebSocket-Version") != "13" {
+			w.Header().Set("Sec-WebSocket-Version", "13")
+			http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+			return
+		}
+		f(w, req, env)
+	})
+	defer v.lock()()
+	if err := v.setHandler(h, funcName(f)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// hasToken reports whether the comma-separated header key lists token,
+// ignoring case.
+func hasToken(h http.Header, key, token string) bool {
+	for _, line := range h.Values(key) {
+		for _, s := range strings.Split(line, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSocket(t *testing.T) {
+	r := &Router{}
+	var kept map[string]string
+	r.Route("/rooms/:room/ws").WebSocket(func(w http.ResponseWriter, r *http.Request, env map[string]string) {
+		kept = env
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	})
+
+	handshake := func(method string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/rooms/go/ws", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	upgrade := map[string]string{
+		"Connection":            "keep-alive, Upgrade",
+		"Upgrade":               "WebSocket",
+		"Sec-WebSocket-Version": "13",
+	}
+
+	w := handshake("GET", upgrade)
+	assert.Equal(t, http.StatusSwitchingProtocols, w.Code)
+	assert.Equal(t, map[string]string{"room": "go"}, kept)
+
+	w = handshake("GET", nil)
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+	assert.Equal(t, "websocket", w.Header().Get("Upgrade"))
+
+	upgrade["Sec-WebSocket-Version"] = "8"
+	w = handshake("GET", upgrade)
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+	assert.Equal(t, "13", w.Header().Get("Sec-WebSocket-Version"))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, handshake("POST", upgrade).Code)
+}